@@ -22,11 +22,21 @@ SQLRETURN sqlSetEnvUIntPtrAttr(SQLHENV environmentHandle, SQLINTEGER attribute,
 SQLRETURN sqlSetConnectUIntPtrAttr(SQLHDBC connectionHandle, SQLINTEGER attribute, uintptr_t valuePtr, SQLINTEGER stringLength) {
 	return SQLSetConnectAttr(connectionHandle, attribute, (SQLPOINTER)valuePtr, stringLength);
 }
+
+SQLRETURN sqlSetStmtUIntPtrAttr(SQLHSTMT statementHandle, SQLINTEGER attribute, uintptr_t valuePtr, SQLINTEGER stringLength) {
+	return SQLSetStmtAttr(statementHandle, attribute, (SQLPOINTER)valuePtr, stringLength);
+}
+
+SQLRETURN sqlSetDescUIntPtrField(SQLHDESC descriptorHandle, SQLSMALLINT recNumber, SQLSMALLINT fieldIdentifier, uintptr_t valuePtr, SQLINTEGER bufferLength) {
+	return SQLSetDescField(descriptorHandle, recNumber, fieldIdentifier, (SQLPOINTER)valuePtr, bufferLength);
+}
 */
 import "C"
 
 const (
-	SQL_OV_ODBC3 = uintptr(C.SQL_OV_ODBC3)
+	SQL_OV_ODBC2    = uintptr(C.SQL_OV_ODBC2)
+	SQL_OV_ODBC3    = uintptr(C.SQL_OV_ODBC3)
+	SQL_OV_ODBC3_80 = uintptr(C.SQL_OV_ODBC3_80)
 
 	SQL_ATTR_ODBC_VERSION = C.SQL_ATTR_ODBC_VERSION
 
@@ -35,11 +45,13 @@ const (
 	SQL_HANDLE_ENV  = C.SQL_HANDLE_ENV
 	SQL_HANDLE_DBC  = C.SQL_HANDLE_DBC
 	SQL_HANDLE_STMT = C.SQL_HANDLE_STMT
+	SQL_HANDLE_DESC = C.SQL_HANDLE_DESC
 
 	SQL_SUCCESS            = C.SQL_SUCCESS
 	SQL_SUCCESS_WITH_INFO  = C.SQL_SUCCESS_WITH_INFO
 	SQL_INVALID_HANDLE     = C.SQL_INVALID_HANDLE
 	SQL_NO_DATA            = C.SQL_NO_DATA
+	SQL_NEED_DATA          = C.SQL_NEED_DATA
 	SQL_NO_TOTAL           = C.SQL_NO_TOTAL
 	SQL_NTS                = C.SQL_NTS
 	SQL_MAX_MESSAGE_LENGTH = C.SQL_MAX_MESSAGE_LENGTH
@@ -48,10 +60,13 @@ const (
 	SQL_NULL_HDBC          = uintptr(C.SQL_NULL_HDBC)
 	SQL_NULL_HSTMT         = uintptr(C.SQL_NULL_HSTMT)
 
-	SQL_PARAM_INPUT = C.SQL_PARAM_INPUT
+	SQL_PARAM_INPUT        = C.SQL_PARAM_INPUT
+	SQL_PARAM_OUTPUT       = C.SQL_PARAM_OUTPUT
+	SQL_PARAM_INPUT_OUTPUT = C.SQL_PARAM_INPUT_OUTPUT
 
-	SQL_NULL_DATA    = C.SQL_NULL_DATA
-	SQL_DATA_AT_EXEC = C.SQL_DATA_AT_EXEC
+	SQL_NULL_DATA               = C.SQL_NULL_DATA
+	SQL_DATA_AT_EXEC            = C.SQL_DATA_AT_EXEC
+	SQL_LEN_DATA_AT_EXEC_OFFSET = C.SQL_LEN_DATA_AT_EXEC_OFFSET
 
 	SQL_UNKNOWN_TYPE    = C.SQL_UNKNOWN_TYPE
 	SQL_CHAR            = C.SQL_CHAR
@@ -104,6 +119,12 @@ const (
 	SQL_C_DEFAULT        = C.SQL_C_DEFAULT
 	SQL_C_SBIGINT        = C.SQL_C_SBIGINT
 	SQL_C_UBIGINT        = C.SQL_C_UBIGINT
+	SQL_C_SLONG          = C.SQL_C_SLONG
+	SQL_C_ULONG          = C.SQL_C_ULONG
+	SQL_C_SSHORT         = C.SQL_C_SSHORT
+	SQL_C_USHORT         = C.SQL_C_USHORT
+	SQL_C_STINYINT       = C.SQL_C_STINYINT
+	SQL_C_UTINYINT       = C.SQL_C_UTINYINT
 	SQL_C_GUID           = C.SQL_C_GUID
 
 	SQL_COMMIT   = C.SQL_COMMIT
@@ -116,6 +137,122 @@ const (
 	SQL_AUTOCOMMIT_DEFAULT = C.SQL_AUTOCOMMIT_DEFAULT
 
 	SQL_IS_UINTEGER = C.SQL_IS_UINTEGER
+	SQL_IS_POINTER  = C.SQL_IS_POINTER
+
+	SQL_ATTR_LOGIN_TIMEOUT      = C.SQL_ATTR_LOGIN_TIMEOUT
+	SQL_ATTR_CONNECTION_TIMEOUT = C.SQL_ATTR_CONNECTION_TIMEOUT
+	SQL_ATTR_QUERY_TIMEOUT      = C.SQL_ATTR_QUERY_TIMEOUT
+
+	SQL_ATTR_CONNECTION_DEAD = C.SQL_ATTR_CONNECTION_DEAD
+	SQL_CD_TRUE              = C.SQL_CD_TRUE
+	SQL_CD_FALSE             = C.SQL_CD_FALSE
+
+	SQL_ATTR_PARAMSET_SIZE        = C.SQL_ATTR_PARAMSET_SIZE
+	SQL_ATTR_PARAM_STATUS_PTR     = C.SQL_ATTR_PARAM_STATUS_PTR
+	SQL_ATTR_PARAMS_PROCESSED_PTR = C.SQL_ATTR_PARAMS_PROCESSED_PTR
+	SQL_PARAM_SUCCESS             = C.SQL_PARAM_SUCCESS
+	SQL_PARAM_SUCCESS_WITH_INFO   = C.SQL_PARAM_SUCCESS_WITH_INFO
+	SQL_PARAM_ERROR               = C.SQL_PARAM_ERROR
+	SQL_PARAM_UNUSED              = C.SQL_PARAM_UNUSED
+	SQL_PARAM_DIAG_UNAVAILABLE    = C.SQL_PARAM_DIAG_UNAVAILABLE
+
+	SQL_ATTR_ROW_ARRAY_SIZE   = C.SQL_ATTR_ROW_ARRAY_SIZE
+	SQL_ATTR_ROW_STATUS_PTR   = C.SQL_ATTR_ROW_STATUS_PTR
+	SQL_ATTR_ROWS_FETCHED_PTR = C.SQL_ATTR_ROWS_FETCHED_PTR
+	SQL_ROW_SUCCESS           = C.SQL_ROW_SUCCESS
+	SQL_ROW_SUCCESS_WITH_INFO = C.SQL_ROW_SUCCESS_WITH_INFO
+	SQL_ROW_ERROR             = C.SQL_ROW_ERROR
+	SQL_ROW_NOROW             = C.SQL_ROW_NOROW
+
+	SQL_ATTR_CURSOR_TYPE       = C.SQL_ATTR_CURSOR_TYPE
+	SQL_CURSOR_FORWARD_ONLY    = C.SQL_CURSOR_FORWARD_ONLY
+	SQL_CURSOR_KEYSET_DRIVEN   = C.SQL_CURSOR_KEYSET_DRIVEN
+	SQL_CURSOR_DYNAMIC         = C.SQL_CURSOR_DYNAMIC
+	SQL_CURSOR_STATIC          = C.SQL_CURSOR_STATIC
+	SQL_ATTR_CURSOR_SCROLLABLE = C.SQL_ATTR_CURSOR_SCROLLABLE
+	SQL_NONSCROLLABLE          = C.SQL_NONSCROLLABLE
+	SQL_SCROLLABLE             = C.SQL_SCROLLABLE
+
+	SQL_SO_FORWARD_ONLY  = C.SQL_SO_FORWARD_ONLY
+	SQL_SO_KEYSET_DRIVEN = C.SQL_SO_KEYSET_DRIVEN
+	SQL_SO_DYNAMIC       = C.SQL_SO_DYNAMIC
+	SQL_SO_MIXED         = C.SQL_SO_MIXED
+	SQL_SO_STATIC        = C.SQL_SO_STATIC
+
+	SQL_FETCH_NEXT     = C.SQL_FETCH_NEXT
+	SQL_FETCH_FIRST    = C.SQL_FETCH_FIRST
+	SQL_FETCH_LAST     = C.SQL_FETCH_LAST
+	SQL_FETCH_PRIOR    = C.SQL_FETCH_PRIOR
+	SQL_FETCH_ABSOLUTE = C.SQL_FETCH_ABSOLUTE
+	SQL_FETCH_RELATIVE = C.SQL_FETCH_RELATIVE
+
+	SQL_CLOSE        = C.SQL_CLOSE
+	SQL_UNBIND       = C.SQL_UNBIND
+	SQL_RESET_PARAMS = C.SQL_RESET_PARAMS
+
+	SQL_ATTR_ACCESS_MODE = C.SQL_ATTR_ACCESS_MODE
+	SQL_MODE_READ_WRITE  = uintptr(C.SQL_MODE_READ_WRITE)
+	SQL_MODE_READ_ONLY   = uintptr(C.SQL_MODE_READ_ONLY)
+
+	SQL_DBMS_NAME   = C.SQL_DBMS_NAME
+	SQL_DBMS_VER    = C.SQL_DBMS_VER
+	SQL_DRIVER_NAME = C.SQL_DRIVER_NAME
+
+	SQL_DRIVER_VER                 = C.SQL_DRIVER_VER
+	SQL_DRIVER_ODBC_VER            = C.SQL_DRIVER_ODBC_VER
+	SQL_ODBC_INTERFACE_CONFORMANCE = C.SQL_ODBC_INTERFACE_CONFORMANCE
+	SQL_SQL_CONFORMANCE            = C.SQL_SQL_CONFORMANCE
+	SQL_IDENTIFIER_QUOTE_CHAR      = C.SQL_IDENTIFIER_QUOTE_CHAR
+	SQL_CATALOG_NAME_SEPARATOR     = C.SQL_CATALOG_NAME_SEPARATOR
+	SQL_SEARCH_PATTERN_ESCAPE      = C.SQL_SEARCH_PATTERN_ESCAPE
+	SQL_KEYWORDS                   = C.SQL_KEYWORDS
+	SQL_CATALOG_TERM               = C.SQL_CATALOG_TERM
+	SQL_SCHEMA_TERM                = C.SQL_SCHEMA_TERM
+	SQL_TABLE_TERM                 = C.SQL_TABLE_TERM
+	SQL_PROCEDURE_TERM             = C.SQL_PROCEDURE_TERM
+	SQL_MAX_CATALOG_NAME_LEN       = C.SQL_MAX_CATALOG_NAME_LEN
+	SQL_MAX_SCHEMA_NAME_LEN        = C.SQL_MAX_SCHEMA_NAME_LEN
+	SQL_MAX_TABLE_NAME_LEN         = C.SQL_MAX_TABLE_NAME_LEN
+	SQL_MAX_COLUMN_NAME_LEN        = C.SQL_MAX_COLUMN_NAME_LEN
+	SQL_DEFAULT_TXN_ISOLATION      = C.SQL_DEFAULT_TXN_ISOLATION
+	SQL_GETDATA_EXTENSIONS         = C.SQL_GETDATA_EXTENSIONS
+	SQL_SCROLL_OPTIONS             = C.SQL_SCROLL_OPTIONS
+
+	SQL_GD_ANY_COLUMN    = C.SQL_GD_ANY_COLUMN
+	SQL_GD_ANY_ORDER     = C.SQL_GD_ANY_ORDER
+	SQL_GD_BLOCK         = C.SQL_GD_BLOCK
+	SQL_GD_BOUND         = C.SQL_GD_BOUND
+	SQL_GD_OUTPUT_PARAMS = C.SQL_GD_OUTPUT_PARAMS
+
+	SQL_SC_SQL92_ENTRY            = C.SQL_SC_SQL92_ENTRY
+	SQL_SC_FIPS127_2_TRANSITIONAL = C.SQL_SC_FIPS127_2_TRANSITIONAL
+	SQL_SC_SQL92_INTERMEDIATE     = C.SQL_SC_SQL92_INTERMEDIATE
+	SQL_SC_SQL92_FULL             = C.SQL_SC_SQL92_FULL
+
+	SQL_OIC_CORE   = C.SQL_OIC_CORE
+	SQL_OIC_LEVEL1 = C.SQL_OIC_LEVEL1
+	SQL_OIC_LEVEL2 = C.SQL_OIC_LEVEL2
+
+	SQL_ATTR_IMP_PARAM_DESC = C.SQL_ATTR_IMP_PARAM_DESC
+	SQL_DESC_NAME           = C.SQL_DESC_NAME
+	SQL_DESC_UNNAMED        = C.SQL_DESC_UNNAMED
+	SQL_NAMED               = C.SQL_NAMED
+	SQL_UNNAMED             = C.SQL_UNNAMED
+
+	SQL_INDEX_UNIQUE = C.SQL_INDEX_UNIQUE
+	SQL_INDEX_ALL    = C.SQL_INDEX_ALL
+	SQL_QUICK        = C.SQL_QUICK
+	SQL_ENSURE       = C.SQL_ENSURE
+	SQL_ALL_TYPES    = C.SQL_ALL_TYPES
+
+	SQL_ATTR_TXN_ISOLATION   = C.SQL_ATTR_TXN_ISOLATION
+	SQL_TXN_ISOLATION_OPTION = C.SQL_TXN_ISOLATION_OPTION
+	SQL_TXN_READ_UNCOMMITTED = C.SQL_TXN_READ_UNCOMMITTED
+	SQL_TXN_READ_COMMITTED   = C.SQL_TXN_READ_COMMITTED
+	SQL_TXN_REPEATABLE_READ  = C.SQL_TXN_REPEATABLE_READ
+	SQL_TXN_SERIALIZABLE     = C.SQL_TXN_SERIALIZABLE
+	// Not defined in sqlext.h. Using value from msodbcsql.h.
+	SQL_TXN_SS_SNAPSHOT = 0x20
 
 	//Connection pooling
 	SQL_ATTR_CONNECTION_POOLING = C.SQL_ATTR_CONNECTION_POOLING
@@ -133,6 +270,7 @@ type (
 	SQLHENV   C.SQLHENV
 	SQLHDBC   C.SQLHDBC
 	SQLHSTMT  C.SQLHSTMT
+	SQLHDESC  C.SQLHDESC
 	SQLHWND   uintptr
 
 	SQLWCHAR     C.SQLWCHAR
@@ -159,3 +297,13 @@ func SQLSetConnectUIntPtrAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, va
 	r := C.sqlSetConnectUIntPtrAttr(C.SQLHDBC(connectionHandle), C.SQLINTEGER(attribute), C.uintptr_t(valuePtr), C.SQLINTEGER(stringLength))
 	return SQLRETURN(r)
 }
+
+func SQLSetStmtUIntPtrAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr uintptr, stringLength SQLINTEGER) (ret SQLRETURN) {
+	r := C.sqlSetStmtUIntPtrAttr(C.SQLHSTMT(statementHandle), C.SQLINTEGER(attribute), C.uintptr_t(valuePtr), C.SQLINTEGER(stringLength))
+	return SQLRETURN(r)
+}
+
+func SQLSetDescUIntPtrField(descriptorHandle SQLHDESC, recNumber SQLSMALLINT, fieldIdentifier SQLSMALLINT, valuePtr uintptr, bufferLength SQLINTEGER) (ret SQLRETURN) {
+	r := C.sqlSetDescUIntPtrField(C.SQLHDESC(descriptorHandle), C.SQLSMALLINT(recNumber), C.SQLSMALLINT(fieldIdentifier), C.uintptr_t(valuePtr), C.SQLINTEGER(bufferLength))
+	return SQLRETURN(r)
+}
@@ -10,7 +10,9 @@ import (
 )
 
 const (
-	SQL_OV_ODBC3 = uintptr(3)
+	SQL_OV_ODBC2    = uintptr(2)
+	SQL_OV_ODBC3    = uintptr(3)
+	SQL_OV_ODBC3_80 = uintptr(380)
 
 	SQL_ATTR_ODBC_VERSION = 200
 
@@ -19,11 +21,13 @@ const (
 	SQL_HANDLE_ENV  = 1
 	SQL_HANDLE_DBC  = 2
 	SQL_HANDLE_STMT = 3
+	SQL_HANDLE_DESC = 4
 
 	SQL_SUCCESS            = 0
 	SQL_SUCCESS_WITH_INFO  = 1
 	SQL_INVALID_HANDLE     = -2
 	SQL_NO_DATA            = 100
+	SQL_NEED_DATA          = 99
 	SQL_NO_TOTAL           = -4
 	SQL_NTS                = -3
 	SQL_MAX_MESSAGE_LENGTH = 512
@@ -32,10 +36,13 @@ const (
 	SQL_NULL_HDBC          = 0
 	SQL_NULL_HSTMT         = 0
 
-	SQL_PARAM_INPUT = 1
+	SQL_PARAM_INPUT        = 1
+	SQL_PARAM_OUTPUT       = 4
+	SQL_PARAM_INPUT_OUTPUT = 2
 
-	SQL_NULL_DATA    = -1
-	SQL_DATA_AT_EXEC = -2
+	SQL_NULL_DATA               = -1
+	SQL_DATA_AT_EXEC            = -2
+	SQL_LEN_DATA_AT_EXEC_OFFSET = -100
 
 	SQL_UNKNOWN_TYPE    = 0
 	SQL_CHAR            = 1
@@ -86,6 +93,12 @@ const (
 	SQL_C_DEFAULT        = 99
 	SQL_C_SBIGINT        = SQL_BIGINT + SQL_SIGNED_OFFSET
 	SQL_C_UBIGINT        = SQL_BIGINT + SQL_UNSIGNED_OFFSET
+	SQL_C_SLONG          = SQL_C_LONG + SQL_SIGNED_OFFSET
+	SQL_C_ULONG          = SQL_C_LONG + SQL_UNSIGNED_OFFSET
+	SQL_C_SSHORT         = SQL_C_SHORT + SQL_SIGNED_OFFSET
+	SQL_C_USHORT         = SQL_C_SHORT + SQL_UNSIGNED_OFFSET
+	SQL_C_STINYINT       = SQL_TINYINT + SQL_SIGNED_OFFSET
+	SQL_C_UTINYINT       = SQL_TINYINT + SQL_UNSIGNED_OFFSET
 	SQL_C_GUID           = SQL_GUID
 
 	SQL_COMMIT   = 0
@@ -98,6 +111,121 @@ const (
 	SQL_AUTOCOMMIT_DEFAULT = SQL_AUTOCOMMIT_ON
 
 	SQL_IS_UINTEGER = -5
+	SQL_IS_POINTER  = -4
+
+	SQL_ATTR_LOGIN_TIMEOUT      = 103
+	SQL_ATTR_CONNECTION_TIMEOUT = 113
+	SQL_ATTR_QUERY_TIMEOUT      = 0
+
+	SQL_ATTR_CONNECTION_DEAD = 1209
+	SQL_CD_TRUE              = 1
+	SQL_CD_FALSE             = 0
+
+	SQL_ATTR_PARAMSET_SIZE        = 22
+	SQL_ATTR_PARAM_STATUS_PTR     = 20
+	SQL_ATTR_PARAMS_PROCESSED_PTR = 21
+	SQL_PARAM_SUCCESS             = 0
+	SQL_PARAM_SUCCESS_WITH_INFO   = 6
+	SQL_PARAM_ERROR               = 5
+	SQL_PARAM_UNUSED              = 7
+	SQL_PARAM_DIAG_UNAVAILABLE    = 1
+
+	SQL_ATTR_ROW_ARRAY_SIZE   = 27
+	SQL_ATTR_ROW_STATUS_PTR   = 25
+	SQL_ATTR_ROWS_FETCHED_PTR = 26
+	SQL_ROW_SUCCESS           = 0
+	SQL_ROW_SUCCESS_WITH_INFO = 6
+	SQL_ROW_ERROR             = 5
+	SQL_ROW_NOROW             = 3
+
+	SQL_ATTR_CURSOR_TYPE       = 6
+	SQL_CURSOR_FORWARD_ONLY    = 0
+	SQL_CURSOR_KEYSET_DRIVEN   = 1
+	SQL_CURSOR_DYNAMIC         = 2
+	SQL_CURSOR_STATIC          = 3
+	SQL_ATTR_CURSOR_SCROLLABLE = -1
+	SQL_NONSCROLLABLE          = 0
+	SQL_SCROLLABLE             = 1
+
+	SQL_SO_FORWARD_ONLY  = 1
+	SQL_SO_KEYSET_DRIVEN = 2
+	SQL_SO_DYNAMIC       = 4
+	SQL_SO_MIXED         = 8
+	SQL_SO_STATIC        = 16
+
+	SQL_FETCH_NEXT     = 1
+	SQL_FETCH_FIRST    = 2
+	SQL_FETCH_LAST     = 3
+	SQL_FETCH_PRIOR    = 4
+	SQL_FETCH_ABSOLUTE = 5
+	SQL_FETCH_RELATIVE = 6
+
+	SQL_CLOSE        = 0
+	SQL_UNBIND       = 2
+	SQL_RESET_PARAMS = 3
+
+	SQL_ATTR_ACCESS_MODE = 101
+	SQL_MODE_READ_WRITE  = uintptr(0)
+	SQL_MODE_READ_ONLY   = uintptr(1)
+
+	SQL_DBMS_NAME   = 17
+	SQL_DBMS_VER    = 18
+	SQL_DRIVER_NAME = 6
+
+	SQL_DRIVER_VER                 = 7
+	SQL_DRIVER_ODBC_VER            = 77
+	SQL_ODBC_INTERFACE_CONFORMANCE = 152
+	SQL_SQL_CONFORMANCE            = 118
+	SQL_IDENTIFIER_QUOTE_CHAR      = 29
+	SQL_CATALOG_NAME_SEPARATOR     = 41
+	SQL_SEARCH_PATTERN_ESCAPE      = 14
+	SQL_KEYWORDS                   = 89
+	SQL_CATALOG_TERM               = 42
+	SQL_SCHEMA_TERM                = 39
+	SQL_TABLE_TERM                 = 45
+	SQL_PROCEDURE_TERM             = 40
+	SQL_MAX_CATALOG_NAME_LEN       = 34
+	SQL_MAX_SCHEMA_NAME_LEN        = 32
+	SQL_MAX_TABLE_NAME_LEN         = 35
+	SQL_MAX_COLUMN_NAME_LEN        = 30
+	SQL_DEFAULT_TXN_ISOLATION      = 26
+	SQL_GETDATA_EXTENSIONS         = 81
+	SQL_SCROLL_OPTIONS             = 44
+
+	SQL_GD_ANY_COLUMN    = 1
+	SQL_GD_ANY_ORDER     = 2
+	SQL_GD_BLOCK         = 4
+	SQL_GD_BOUND         = 8
+	SQL_GD_OUTPUT_PARAMS = 16
+
+	SQL_SC_SQL92_ENTRY            = 1
+	SQL_SC_FIPS127_2_TRANSITIONAL = 2
+	SQL_SC_SQL92_INTERMEDIATE     = 4
+	SQL_SC_SQL92_FULL             = 8
+
+	SQL_OIC_CORE   = 1
+	SQL_OIC_LEVEL1 = 2
+	SQL_OIC_LEVEL2 = 3
+
+	SQL_ATTR_IMP_PARAM_DESC = 10013
+	SQL_DESC_NAME           = 1011
+	SQL_DESC_UNNAMED        = 1012
+	SQL_NAMED               = 0
+	SQL_UNNAMED             = 1
+
+	SQL_INDEX_UNIQUE = 0
+	SQL_INDEX_ALL    = 1
+	SQL_QUICK        = 0
+	SQL_ENSURE       = 1
+	SQL_ALL_TYPES    = 0
+
+	SQL_ATTR_TXN_ISOLATION   = 108
+	SQL_TXN_ISOLATION_OPTION = 72
+	SQL_TXN_READ_UNCOMMITTED = 1
+	SQL_TXN_READ_COMMITTED   = 2
+	SQL_TXN_REPEATABLE_READ  = 4
+	SQL_TXN_SERIALIZABLE     = 8
+	SQL_TXN_SS_SNAPSHOT      = 0x20
 
 	//Connection pooling
 	SQL_ATTR_CONNECTION_POOLING = 201
@@ -115,6 +243,7 @@ type (
 	SQLHENV   SQLHANDLE
 	SQLHDBC   SQLHANDLE
 	SQLHSTMT  SQLHANDLE
+	SQLHDESC  SQLHANDLE
 	SQLHWND   uintptr
 
 	SQLWCHAR     uint16
@@ -145,3 +274,15 @@ func SQLSetConnectUIntPtrAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, va
 	ret = SQLRETURN(r0)
 	return
 }
+
+func SQLSetStmtUIntPtrAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr uintptr, stringLength SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLSetStmtAttrW.Addr(), 4, uintptr(statementHandle), uintptr(attribute), uintptr(valuePtr), uintptr(stringLength), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLSetDescUIntPtrField(descriptorHandle SQLHDESC, recNumber SQLSMALLINT, fieldIdentifier SQLSMALLINT, valuePtr uintptr, bufferLength SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLSetDescFieldW.Addr(), 5, uintptr(descriptorHandle), uintptr(recNumber), uintptr(fieldIdentifier), uintptr(valuePtr), uintptr(bufferLength), 0)
+	ret = SQLRETURN(r0)
+	return
+}
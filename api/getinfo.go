@@ -0,0 +1,55 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"math"
+	"unsafe"
+)
+
+// maxInfoChars is length, in uint16, of the largest buffer, whose
+// size in bytes fits SQLSMALLINT.
+const maxInfoChars = math.MaxInt16 / 2
+
+// SQLGetInfoString calls SQLGetInfo for information type infoType
+// that returns character string.
+func SQLGetInfoString(connectionHandle SQLHDBC, infoType SQLUSMALLINT) (string, SQLRETURN) {
+	b := make([]uint16, 128)
+	for {
+		var l SQLSMALLINT
+		ret := SQLGetInfo(connectionHandle, infoType, SQLPOINTER(unsafe.Pointer(&b[0])), SQLSMALLINT(2*len(b)), &l)
+		if ret != SQL_SUCCESS && ret != SQL_SUCCESS_WITH_INFO {
+			return "", ret
+		}
+		n := int(l) / 2 // l is in bytes
+		if n < len(b) {
+			return UTF16ToString(b[:n]), ret
+		}
+		if len(b) == maxInfoChars {
+			// Longer value can not be returned, keep it truncated.
+			return UTF16ToString(b[:len(b)-1]), ret
+		}
+		if n >= maxInfoChars {
+			n = maxInfoChars - 1
+		}
+		b = make([]uint16, n+1)
+	}
+}
+
+// SQLGetInfoUSmallInt calls SQLGetInfo for information type
+// infoType that returns SQLUSMALLINT.
+func SQLGetInfoUSmallInt(connectionHandle SQLHDBC, infoType SQLUSMALLINT) (SQLUSMALLINT, SQLRETURN) {
+	var v SQLUSMALLINT
+	ret := SQLGetInfo(connectionHandle, infoType, SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+	return v, ret
+}
+
+// SQLGetInfoUInteger calls SQLGetInfo for information type
+// infoType that returns SQLUINTEGER value or bitmask.
+func SQLGetInfoUInteger(connectionHandle SQLHDBC, infoType SQLUSMALLINT) (SQLUINTEGER, SQLRETURN) {
+	var v SQLUINTEGER
+	ret := SQLGetInfo(connectionHandle, infoType, SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+	return v, ret
+}
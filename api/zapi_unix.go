@@ -35,11 +35,26 @@ func SQLBindParameter(statementHandle SQLHSTMT, parameterNumber SQLUSMALLINT, in
 	return SQLRETURN(r)
 }
 
+func SQLCancel(statementHandle SQLHSTMT) (ret SQLRETURN) {
+	r := C.SQLCancel(C.SQLHSTMT(statementHandle))
+	return SQLRETURN(r)
+}
+
+func SQLCancelHandle(handleType SQLSMALLINT, handle SQLHANDLE) (ret SQLRETURN) {
+	r := C.SQLCancelHandle(C.SQLSMALLINT(handleType), C.SQLHANDLE(handle))
+	return SQLRETURN(r)
+}
+
 func SQLCloseCursor(statementHandle SQLHSTMT) (ret SQLRETURN) {
 	r := C.SQLCloseCursor(C.SQLHSTMT(statementHandle))
 	return SQLRETURN(r)
 }
 
+func SQLColumns(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, columnName *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLColumnsW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(tableName)), C.SQLSMALLINT(nameLength3), (*C.SQLWCHAR)(unsafe.Pointer(columnName)), C.SQLSMALLINT(nameLength4))
+	return SQLRETURN(r)
+}
+
 func SQLDescribeCol(statementHandle SQLHSTMT, columnNumber SQLUSMALLINT, columnName *SQLWCHAR, bufferLength SQLSMALLINT, nameLengthPtr *SQLSMALLINT, dataTypePtr *SQLSMALLINT, columnSizePtr *SQLULEN, decimalDigitsPtr *SQLSMALLINT, nullablePtr *SQLSMALLINT) (ret SQLRETURN) {
 	r := C.SQLDescribeColW(C.SQLHSTMT(statementHandle), C.SQLUSMALLINT(columnNumber), (*C.SQLWCHAR)(unsafe.Pointer(columnName)), C.SQLSMALLINT(bufferLength), (*C.SQLSMALLINT)(nameLengthPtr), (*C.SQLSMALLINT)(dataTypePtr), (*C.SQLULEN)(columnSizePtr), (*C.SQLSMALLINT)(decimalDigitsPtr), (*C.SQLSMALLINT)(nullablePtr))
 	return SQLRETURN(r)
@@ -75,11 +90,31 @@ func SQLFetch(statementHandle SQLHSTMT) (ret SQLRETURN) {
 	return SQLRETURN(r)
 }
 
+func SQLFetchScroll(statementHandle SQLHSTMT, fetchOrientation SQLSMALLINT, fetchOffset SQLLEN) (ret SQLRETURN) {
+	r := C.SQLFetchScroll(C.SQLHSTMT(statementHandle), C.SQLSMALLINT(fetchOrientation), C.SQLLEN(fetchOffset))
+	return SQLRETURN(r)
+}
+
+func SQLForeignKeys(statementHandle SQLHSTMT, pkCatalogName *SQLWCHAR, nameLength1 SQLSMALLINT, pkSchemaName *SQLWCHAR, nameLength2 SQLSMALLINT, pkTableName *SQLWCHAR, nameLength3 SQLSMALLINT, fkCatalogName *SQLWCHAR, nameLength4 SQLSMALLINT, fkSchemaName *SQLWCHAR, nameLength5 SQLSMALLINT, fkTableName *SQLWCHAR, nameLength6 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLForeignKeysW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(pkCatalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(pkSchemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(pkTableName)), C.SQLSMALLINT(nameLength3), (*C.SQLWCHAR)(unsafe.Pointer(fkCatalogName)), C.SQLSMALLINT(nameLength4), (*C.SQLWCHAR)(unsafe.Pointer(fkSchemaName)), C.SQLSMALLINT(nameLength5), (*C.SQLWCHAR)(unsafe.Pointer(fkTableName)), C.SQLSMALLINT(nameLength6))
+	return SQLRETURN(r)
+}
+
 func SQLFreeHandle(handleType SQLSMALLINT, handle SQLHANDLE) (ret SQLRETURN) {
 	r := C.SQLFreeHandle(C.SQLSMALLINT(handleType), C.SQLHANDLE(handle))
 	return SQLRETURN(r)
 }
 
+func SQLFreeStmt(statementHandle SQLHSTMT, option SQLUSMALLINT) (ret SQLRETURN) {
+	r := C.SQLFreeStmt(C.SQLHSTMT(statementHandle), C.SQLUSMALLINT(option))
+	return SQLRETURN(r)
+}
+
+func SQLGetConnectAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, valuePtr SQLPOINTER, bufferLength SQLINTEGER, stringLengthPtr *SQLINTEGER) (ret SQLRETURN) {
+	r := C.SQLGetConnectAttrW(C.SQLHDBC(connectionHandle), C.SQLINTEGER(attribute), C.SQLPOINTER(valuePtr), C.SQLINTEGER(bufferLength), (*C.SQLINTEGER)(stringLengthPtr))
+	return SQLRETURN(r)
+}
+
 func SQLGetData(statementHandle SQLHSTMT, colOrParamNum SQLUSMALLINT, targetType SQLSMALLINT, targetValuePtr SQLPOINTER, bufferLength SQLLEN, vallen *SQLLEN) (ret SQLRETURN) {
 	r := C.SQLGetData(C.SQLHSTMT(statementHandle), C.SQLUSMALLINT(colOrParamNum), C.SQLSMALLINT(targetType), C.SQLPOINTER(targetValuePtr), C.SQLLEN(bufferLength), (*C.SQLLEN)(vallen))
 	return SQLRETURN(r)
@@ -90,6 +125,21 @@ func SQLGetDiagRec(handleType SQLSMALLINT, handle SQLHANDLE, recNumber SQLSMALLI
 	return SQLRETURN(r)
 }
 
+func SQLGetInfo(connectionHandle SQLHDBC, infoType SQLUSMALLINT, infoValuePtr SQLPOINTER, bufferLength SQLSMALLINT, stringLengthPtr *SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLGetInfoW(C.SQLHDBC(connectionHandle), C.SQLUSMALLINT(infoType), C.SQLPOINTER(infoValuePtr), C.SQLSMALLINT(bufferLength), (*C.SQLSMALLINT)(stringLengthPtr))
+	return SQLRETURN(r)
+}
+
+func SQLGetStmtAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr SQLPOINTER, bufferLength SQLINTEGER, stringLengthPtr *SQLINTEGER) (ret SQLRETURN) {
+	r := C.SQLGetStmtAttrW(C.SQLHSTMT(statementHandle), C.SQLINTEGER(attribute), C.SQLPOINTER(valuePtr), C.SQLINTEGER(bufferLength), (*C.SQLINTEGER)(stringLengthPtr))
+	return SQLRETURN(r)
+}
+
+func SQLGetTypeInfo(statementHandle SQLHSTMT, dataType SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLGetTypeInfoW(C.SQLHSTMT(statementHandle), C.SQLSMALLINT(dataType))
+	return SQLRETURN(r)
+}
+
 func SQLNumParams(statementHandle SQLHSTMT, parameterCountPtr *SQLSMALLINT) (ret SQLRETURN) {
 	r := C.SQLNumParams(C.SQLHSTMT(statementHandle), (*C.SQLSMALLINT)(parameterCountPtr))
 	return SQLRETURN(r)
@@ -105,11 +155,36 @@ func SQLNumResultCols(statementHandle SQLHSTMT, columnCountPtr *SQLSMALLINT) (re
 	return SQLRETURN(r)
 }
 
+func SQLParamData(statementHandle SQLHSTMT, valuePtrPtr *SQLPOINTER) (ret SQLRETURN) {
+	r := C.SQLParamData(C.SQLHSTMT(statementHandle), (*C.SQLPOINTER)(valuePtrPtr))
+	return SQLRETURN(r)
+}
+
 func SQLPrepare(statementHandle SQLHSTMT, statementText *SQLWCHAR, textLength SQLINTEGER) (ret SQLRETURN) {
 	r := C.SQLPrepareW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(statementText)), C.SQLINTEGER(textLength))
 	return SQLRETURN(r)
 }
 
+func SQLPrimaryKeys(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLPrimaryKeysW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(tableName)), C.SQLSMALLINT(nameLength3))
+	return SQLRETURN(r)
+}
+
+func SQLProcedureColumns(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, procName *SQLWCHAR, nameLength3 SQLSMALLINT, columnName *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLProcedureColumnsW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(procName)), C.SQLSMALLINT(nameLength3), (*C.SQLWCHAR)(unsafe.Pointer(columnName)), C.SQLSMALLINT(nameLength4))
+	return SQLRETURN(r)
+}
+
+func SQLProcedures(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, procName *SQLWCHAR, nameLength3 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLProceduresW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(procName)), C.SQLSMALLINT(nameLength3))
+	return SQLRETURN(r)
+}
+
+func SQLPutData(statementHandle SQLHSTMT, dataPtr SQLPOINTER, strLen_or_Ind SQLLEN) (ret SQLRETURN) {
+	r := C.SQLPutData(C.SQLHSTMT(statementHandle), C.SQLPOINTER(dataPtr), C.SQLLEN(strLen_or_Ind))
+	return SQLRETURN(r)
+}
+
 func SQLRowCount(statementHandle SQLHSTMT, rowCountPtr *SQLLEN) (ret SQLRETURN) {
 	r := C.SQLRowCount(C.SQLHSTMT(statementHandle), (*C.SQLLEN)(rowCountPtr))
 	return SQLRETURN(r)
@@ -124,3 +199,23 @@ func SQLSetConnectAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, valuePtr
 	r := C.SQLSetConnectAttrW(C.SQLHDBC(connectionHandle), C.SQLINTEGER(attribute), C.SQLPOINTER(valuePtr), C.SQLINTEGER(stringLength))
 	return SQLRETURN(r)
 }
+
+func SQLSetDescField(descriptorHandle SQLHDESC, recNumber SQLSMALLINT, fieldIdentifier SQLSMALLINT, valuePtr SQLPOINTER, bufferLength SQLINTEGER) (ret SQLRETURN) {
+	r := C.SQLSetDescFieldW(C.SQLHDESC(descriptorHandle), C.SQLSMALLINT(recNumber), C.SQLSMALLINT(fieldIdentifier), C.SQLPOINTER(valuePtr), C.SQLINTEGER(bufferLength))
+	return SQLRETURN(r)
+}
+
+func SQLSetStmtAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr SQLPOINTER, stringLength SQLINTEGER) (ret SQLRETURN) {
+	r := C.SQLSetStmtAttrW(C.SQLHSTMT(statementHandle), C.SQLINTEGER(attribute), C.SQLPOINTER(valuePtr), C.SQLINTEGER(stringLength))
+	return SQLRETURN(r)
+}
+
+func SQLStatistics(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, unique SQLUSMALLINT, reserved SQLUSMALLINT) (ret SQLRETURN) {
+	r := C.SQLStatisticsW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(tableName)), C.SQLSMALLINT(nameLength3), C.SQLUSMALLINT(unique), C.SQLUSMALLINT(reserved))
+	return SQLRETURN(r)
+}
+
+func SQLTables(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, tableType *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r := C.SQLTablesW(C.SQLHSTMT(statementHandle), (*C.SQLWCHAR)(unsafe.Pointer(catalogName)), C.SQLSMALLINT(nameLength1), (*C.SQLWCHAR)(unsafe.Pointer(schemaName)), C.SQLSMALLINT(nameLength2), (*C.SQLWCHAR)(unsafe.Pointer(tableName)), C.SQLSMALLINT(nameLength3), (*C.SQLWCHAR)(unsafe.Pointer(tableType)), C.SQLSMALLINT(nameLength4))
+	return SQLRETURN(r)
+}
@@ -39,27 +39,46 @@ func errnoErr(e syscall.Errno) error {
 var (
 	mododbc32 = windows.NewLazySystemDLL("odbc32.dll")
 
-	procSQLAllocHandle     = mododbc32.NewProc("SQLAllocHandle")
-	procSQLBindCol         = mododbc32.NewProc("SQLBindCol")
-	procSQLBindParameter   = mododbc32.NewProc("SQLBindParameter")
-	procSQLCloseCursor     = mododbc32.NewProc("SQLCloseCursor")
-	procSQLDescribeColW    = mododbc32.NewProc("SQLDescribeColW")
-	procSQLDescribeParam   = mododbc32.NewProc("SQLDescribeParam")
-	procSQLDisconnect      = mododbc32.NewProc("SQLDisconnect")
-	procSQLDriverConnectW  = mododbc32.NewProc("SQLDriverConnectW")
-	procSQLEndTran         = mododbc32.NewProc("SQLEndTran")
-	procSQLExecute         = mododbc32.NewProc("SQLExecute")
-	procSQLFetch           = mododbc32.NewProc("SQLFetch")
-	procSQLFreeHandle      = mododbc32.NewProc("SQLFreeHandle")
-	procSQLGetData         = mododbc32.NewProc("SQLGetData")
-	procSQLGetDiagRecW     = mododbc32.NewProc("SQLGetDiagRecW")
-	procSQLNumParams       = mododbc32.NewProc("SQLNumParams")
-	procSQLMoreResults     = mododbc32.NewProc("SQLMoreResults")
-	procSQLNumResultCols   = mododbc32.NewProc("SQLNumResultCols")
-	procSQLPrepareW        = mododbc32.NewProc("SQLPrepareW")
-	procSQLRowCount        = mododbc32.NewProc("SQLRowCount")
-	procSQLSetEnvAttr      = mododbc32.NewProc("SQLSetEnvAttr")
-	procSQLSetConnectAttrW = mododbc32.NewProc("SQLSetConnectAttrW")
+	procSQLAllocHandle       = mododbc32.NewProc("SQLAllocHandle")
+	procSQLBindCol           = mododbc32.NewProc("SQLBindCol")
+	procSQLBindParameter     = mododbc32.NewProc("SQLBindParameter")
+	procSQLCancel            = mododbc32.NewProc("SQLCancel")
+	procSQLCancelHandle      = mododbc32.NewProc("SQLCancelHandle")
+	procSQLCloseCursor       = mododbc32.NewProc("SQLCloseCursor")
+	procSQLColumnsW          = mododbc32.NewProc("SQLColumnsW")
+	procSQLDescribeColW      = mododbc32.NewProc("SQLDescribeColW")
+	procSQLDescribeParam     = mododbc32.NewProc("SQLDescribeParam")
+	procSQLDisconnect        = mododbc32.NewProc("SQLDisconnect")
+	procSQLDriverConnectW    = mododbc32.NewProc("SQLDriverConnectW")
+	procSQLEndTran           = mododbc32.NewProc("SQLEndTran")
+	procSQLExecute           = mododbc32.NewProc("SQLExecute")
+	procSQLFetch             = mododbc32.NewProc("SQLFetch")
+	procSQLFetchScroll       = mododbc32.NewProc("SQLFetchScroll")
+	procSQLForeignKeysW      = mododbc32.NewProc("SQLForeignKeysW")
+	procSQLFreeHandle        = mododbc32.NewProc("SQLFreeHandle")
+	procSQLFreeStmt          = mododbc32.NewProc("SQLFreeStmt")
+	procSQLGetConnectAttrW   = mododbc32.NewProc("SQLGetConnectAttrW")
+	procSQLGetData           = mododbc32.NewProc("SQLGetData")
+	procSQLGetDiagRecW       = mododbc32.NewProc("SQLGetDiagRecW")
+	procSQLGetInfoW          = mododbc32.NewProc("SQLGetInfoW")
+	procSQLGetStmtAttrW      = mododbc32.NewProc("SQLGetStmtAttrW")
+	procSQLGetTypeInfoW      = mododbc32.NewProc("SQLGetTypeInfoW")
+	procSQLNumParams         = mododbc32.NewProc("SQLNumParams")
+	procSQLMoreResults       = mododbc32.NewProc("SQLMoreResults")
+	procSQLNumResultCols     = mododbc32.NewProc("SQLNumResultCols")
+	procSQLParamData         = mododbc32.NewProc("SQLParamData")
+	procSQLPrepareW          = mododbc32.NewProc("SQLPrepareW")
+	procSQLPrimaryKeysW      = mododbc32.NewProc("SQLPrimaryKeysW")
+	procSQLProcedureColumnsW = mododbc32.NewProc("SQLProcedureColumnsW")
+	procSQLProceduresW       = mododbc32.NewProc("SQLProceduresW")
+	procSQLPutData           = mododbc32.NewProc("SQLPutData")
+	procSQLRowCount          = mododbc32.NewProc("SQLRowCount")
+	procSQLSetEnvAttr        = mododbc32.NewProc("SQLSetEnvAttr")
+	procSQLSetConnectAttrW   = mododbc32.NewProc("SQLSetConnectAttrW")
+	procSQLSetDescFieldW     = mododbc32.NewProc("SQLSetDescFieldW")
+	procSQLSetStmtAttrW      = mododbc32.NewProc("SQLSetStmtAttrW")
+	procSQLStatisticsW       = mododbc32.NewProc("SQLStatisticsW")
+	procSQLTablesW           = mododbc32.NewProc("SQLTablesW")
 )
 
 func SQLAllocHandle(handleType SQLSMALLINT, inputHandle SQLHANDLE, outputHandle *SQLHANDLE) (ret SQLRETURN) {
@@ -80,12 +99,30 @@ func SQLBindParameter(statementHandle SQLHSTMT, parameterNumber SQLUSMALLINT, in
 	return
 }
 
+func SQLCancel(statementHandle SQLHSTMT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLCancel.Addr(), 1, uintptr(statementHandle), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLCancelHandle(handleType SQLSMALLINT, handle SQLHANDLE) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLCancelHandle.Addr(), 2, uintptr(handleType), uintptr(handle), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLCloseCursor(statementHandle SQLHSTMT) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall(procSQLCloseCursor.Addr(), 1, uintptr(statementHandle), 0, 0)
 	ret = SQLRETURN(r0)
 	return
 }
 
+func SQLColumns(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, columnName *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLColumnsW.Addr(), 9, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(tableName)), uintptr(nameLength3), uintptr(unsafe.Pointer(columnName)), uintptr(nameLength4))
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLDescribeCol(statementHandle SQLHSTMT, columnNumber SQLUSMALLINT, columnName *SQLWCHAR, bufferLength SQLSMALLINT, nameLengthPtr *SQLSMALLINT, dataTypePtr *SQLSMALLINT, columnSizePtr *SQLULEN, decimalDigitsPtr *SQLSMALLINT, nullablePtr *SQLSMALLINT) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall9(procSQLDescribeColW.Addr(), 9, uintptr(statementHandle), uintptr(columnNumber), uintptr(unsafe.Pointer(columnName)), uintptr(bufferLength), uintptr(unsafe.Pointer(nameLengthPtr)), uintptr(unsafe.Pointer(dataTypePtr)), uintptr(unsafe.Pointer(columnSizePtr)), uintptr(unsafe.Pointer(decimalDigitsPtr)), uintptr(unsafe.Pointer(nullablePtr)))
 	ret = SQLRETURN(r0)
@@ -128,12 +165,36 @@ func SQLFetch(statementHandle SQLHSTMT) (ret SQLRETURN) {
 	return
 }
 
+func SQLFetchScroll(statementHandle SQLHSTMT, fetchOrientation SQLSMALLINT, fetchOffset SQLLEN) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLFetchScroll.Addr(), 3, uintptr(statementHandle), uintptr(fetchOrientation), uintptr(fetchOffset))
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLForeignKeys(statementHandle SQLHSTMT, pkCatalogName *SQLWCHAR, nameLength1 SQLSMALLINT, pkSchemaName *SQLWCHAR, nameLength2 SQLSMALLINT, pkTableName *SQLWCHAR, nameLength3 SQLSMALLINT, fkCatalogName *SQLWCHAR, nameLength4 SQLSMALLINT, fkSchemaName *SQLWCHAR, nameLength5 SQLSMALLINT, fkTableName *SQLWCHAR, nameLength6 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall15(procSQLForeignKeysW.Addr(), 13, uintptr(statementHandle), uintptr(unsafe.Pointer(pkCatalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(pkSchemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(pkTableName)), uintptr(nameLength3), uintptr(unsafe.Pointer(fkCatalogName)), uintptr(nameLength4), uintptr(unsafe.Pointer(fkSchemaName)), uintptr(nameLength5), uintptr(unsafe.Pointer(fkTableName)), uintptr(nameLength6), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLFreeHandle(handleType SQLSMALLINT, handle SQLHANDLE) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall(procSQLFreeHandle.Addr(), 2, uintptr(handleType), uintptr(handle), 0)
 	ret = SQLRETURN(r0)
 	return
 }
 
+func SQLFreeStmt(statementHandle SQLHSTMT, option SQLUSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLFreeStmt.Addr(), 2, uintptr(statementHandle), uintptr(option), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLGetConnectAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, valuePtr SQLPOINTER, bufferLength SQLINTEGER, stringLengthPtr *SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLGetConnectAttrW.Addr(), 5, uintptr(connectionHandle), uintptr(attribute), uintptr(valuePtr), uintptr(bufferLength), uintptr(unsafe.Pointer(stringLengthPtr)), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLGetData(statementHandle SQLHSTMT, colOrParamNum SQLUSMALLINT, targetType SQLSMALLINT, targetValuePtr SQLPOINTER, bufferLength SQLLEN, vallen *SQLLEN) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall6(procSQLGetData.Addr(), 6, uintptr(statementHandle), uintptr(colOrParamNum), uintptr(targetType), uintptr(targetValuePtr), uintptr(bufferLength), uintptr(unsafe.Pointer(vallen)))
 	ret = SQLRETURN(r0)
@@ -146,6 +207,24 @@ func SQLGetDiagRec(handleType SQLSMALLINT, handle SQLHANDLE, recNumber SQLSMALLI
 	return
 }
 
+func SQLGetInfo(connectionHandle SQLHDBC, infoType SQLUSMALLINT, infoValuePtr SQLPOINTER, bufferLength SQLSMALLINT, stringLengthPtr *SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLGetInfoW.Addr(), 5, uintptr(connectionHandle), uintptr(infoType), uintptr(infoValuePtr), uintptr(bufferLength), uintptr(unsafe.Pointer(stringLengthPtr)), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLGetStmtAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr SQLPOINTER, bufferLength SQLINTEGER, stringLengthPtr *SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLGetStmtAttrW.Addr(), 5, uintptr(statementHandle), uintptr(attribute), uintptr(valuePtr), uintptr(bufferLength), uintptr(unsafe.Pointer(stringLengthPtr)), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLGetTypeInfo(statementHandle SQLHSTMT, dataType SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLGetTypeInfoW.Addr(), 2, uintptr(statementHandle), uintptr(dataType), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLNumParams(statementHandle SQLHSTMT, parameterCountPtr *SQLSMALLINT) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall(procSQLNumParams.Addr(), 2, uintptr(statementHandle), uintptr(unsafe.Pointer(parameterCountPtr)), 0)
 	ret = SQLRETURN(r0)
@@ -164,12 +243,42 @@ func SQLNumResultCols(statementHandle SQLHSTMT, columnCountPtr *SQLSMALLINT) (re
 	return
 }
 
+func SQLParamData(statementHandle SQLHSTMT, valuePtrPtr *SQLPOINTER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLParamData.Addr(), 2, uintptr(statementHandle), uintptr(unsafe.Pointer(valuePtrPtr)), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLPrepare(statementHandle SQLHSTMT, statementText *SQLWCHAR, textLength SQLINTEGER) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall(procSQLPrepareW.Addr(), 3, uintptr(statementHandle), uintptr(unsafe.Pointer(statementText)), uintptr(textLength))
 	ret = SQLRETURN(r0)
 	return
 }
 
+func SQLPrimaryKeys(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLPrimaryKeysW.Addr(), 7, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(tableName)), uintptr(nameLength3), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLProcedureColumns(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, procName *SQLWCHAR, nameLength3 SQLSMALLINT, columnName *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLProcedureColumnsW.Addr(), 9, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(procName)), uintptr(nameLength3), uintptr(unsafe.Pointer(columnName)), uintptr(nameLength4))
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLProcedures(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, procName *SQLWCHAR, nameLength3 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLProceduresW.Addr(), 7, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(procName)), uintptr(nameLength3), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLPutData(statementHandle SQLHSTMT, dataPtr SQLPOINTER, strLen_or_Ind SQLLEN) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall(procSQLPutData.Addr(), 3, uintptr(statementHandle), uintptr(dataPtr), uintptr(strLen_or_Ind))
+	ret = SQLRETURN(r0)
+	return
+}
+
 func SQLRowCount(statementHandle SQLHSTMT, rowCountPtr *SQLLEN) (ret SQLRETURN) {
 	r0, _, _ := syscall.Syscall(procSQLRowCount.Addr(), 2, uintptr(statementHandle), uintptr(unsafe.Pointer(rowCountPtr)), 0)
 	ret = SQLRETURN(r0)
@@ -187,3 +296,27 @@ func SQLSetConnectAttr(connectionHandle SQLHDBC, attribute SQLINTEGER, valuePtr
 	ret = SQLRETURN(r0)
 	return
 }
+
+func SQLSetDescField(descriptorHandle SQLHDESC, recNumber SQLSMALLINT, fieldIdentifier SQLSMALLINT, valuePtr SQLPOINTER, bufferLength SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLSetDescFieldW.Addr(), 5, uintptr(descriptorHandle), uintptr(recNumber), uintptr(fieldIdentifier), uintptr(valuePtr), uintptr(bufferLength), 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLSetStmtAttr(statementHandle SQLHSTMT, attribute SQLINTEGER, valuePtr SQLPOINTER, stringLength SQLINTEGER) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall6(procSQLSetStmtAttrW.Addr(), 4, uintptr(statementHandle), uintptr(attribute), uintptr(valuePtr), uintptr(stringLength), 0, 0)
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLStatistics(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, unique SQLUSMALLINT, reserved SQLUSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLStatisticsW.Addr(), 9, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(tableName)), uintptr(nameLength3), uintptr(unique), uintptr(reserved))
+	ret = SQLRETURN(r0)
+	return
+}
+
+func SQLTables(statementHandle SQLHSTMT, catalogName *SQLWCHAR, nameLength1 SQLSMALLINT, schemaName *SQLWCHAR, nameLength2 SQLSMALLINT, tableName *SQLWCHAR, nameLength3 SQLSMALLINT, tableType *SQLWCHAR, nameLength4 SQLSMALLINT) (ret SQLRETURN) {
+	r0, _, _ := syscall.Syscall9(procSQLTablesW.Addr(), 9, uintptr(statementHandle), uintptr(unsafe.Pointer(catalogName)), uintptr(nameLength1), uintptr(unsafe.Pointer(schemaName)), uintptr(nameLength2), uintptr(unsafe.Pointer(tableName)), uintptr(nameLength3), uintptr(unsafe.Pointer(tableType)), uintptr(nameLength4))
+	ret = SQLRETURN(r0)
+	return
+}
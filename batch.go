@@ -0,0 +1,552 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// defaultBatchSize is the number of rows ExecBatch sends
+// with one SQLExecute, if Config.BatchSize is not set.
+const defaultBatchSize = 1000
+
+// maxBatchParams limits number of parameter values sent with one
+// SQLExecute, because some drivers count them in 16 bit integers.
+const maxBatchParams = 65535
+
+// maxBatchBytes limits size of parameter arrays sent with one
+// SQLExecute, because every string and binary value takes as
+// much space as the longest value of its parameter.
+const maxBatchBytes = 16 << 20
+
+// BatchError is returned by ExecBatch if the driver fails to
+// execute some rows. Rows after the failed chunk are not executed.
+type BatchError struct {
+	// Failed are indexes of failed rows, as passed to ExecBatch.
+	// It is empty, if the driver does not report row status.
+	Failed []int
+	// Processed is the number of rows the driver processed,
+	// including failed rows.
+	Processed int
+	// RowsAffected is the number of rows affected by
+	// successfully executed chunks.
+	RowsAffected int64
+	// Err is the error returned by the driver.
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Failed) == 0 {
+		return fmt.Sprintf("odbc: batch failed after %d rows: %v", e.Processed, e.Err)
+	}
+	return fmt.Sprintf("odbc: %d batch rows failed, first is row %d: %v", len(e.Failed), e.Failed[0], e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ExecBatch executes s once for every row of rows. Rows are sent to
+// the driver in chunks, as arrays of parameters (SQL_ATTR_PARAMSET_SIZE),
+// so a chunk needs only one round trip. Values of a parameter must have
+// the same type in all rows, or be nil. Parameters are ? markers,
+// named markers are not supported. ExecBatch is meant for use with
+// sql.Conn.Raw:
+//
+//	err := conn.Raw(func(dc interface{}) error {
+//		st, err := dc.(*odbc.Conn).PrepareContext(ctx, "insert into t values (?, ?)")
+//		if err != nil {
+//			return err
+//		}
+//		defer st.Close()
+//		_, err = st.(*odbc.Stmt).ExecBatchContext(ctx, rows)
+//		return err
+//	})
+func (s *Stmt) ExecBatch(rows [][]interface{}) (driver.Result, error) {
+	return s.ExecBatchContext(context.Background(), rows)
+}
+
+// ExecBatchContext is like ExecBatch, but the batch is
+// canceled if ctx is done before it completes.
+func (s *Stmt) ExecBatchContext(ctx context.Context, rows [][]interface{}) (driver.Result, error) {
+	if s.closed {
+		return nil, errors.New("Stmt is closed")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	vals := make([][]driver.Value, len(rows))
+	for i, row := range rows {
+		vals[i] = make([]driver.Value, len(row))
+		for j, v := range row {
+			dv, err := s.c.batchValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("odbc: batch row %d, parameter %d: %v", i, j+1, err)
+			}
+			vals[i][j] = dv
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.prepareFor(nil, CursorForwardOnly); err != nil {
+		return nil, err
+	}
+	n := len(s.os.Parameters)
+	if s.placeholders != nil && n != len(s.placeholders) {
+		return nil, errors.New("odbc: ExecBatch does not support named parameter markers, use ?")
+	}
+	for i, row := range vals {
+		if len(row) != n {
+			return nil, fmt.Errorf("odbc: batch row %d has %d values, %d expected", i, len(row), n)
+		}
+	}
+	if len(vals) == 0 {
+		return &Result{}, nil
+	}
+	if err := s.os.setQueryTimeout(ctx, s.c.cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+	stop := s.os.watchCancel(ctx)
+	r, err := s.os.execBatch(vals, batchChunkSize(s.c.cfg.BatchSize, n), s.c)
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, wrapTimeout(err)
+	}
+	return r, nil
+}
+
+// batchChunkSize returns number of rows sent with one SQLExecute.
+func batchChunkSize(batchSize, numParams int) int {
+	n := batchSize
+	if n <= 0 {
+		n = defaultBatchSize
+	}
+	if numParams > 0 && n*numParams > maxBatchParams {
+		n = maxBatchParams / numParams
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// batchChunkEnd returns end of chunk of at most chunk rows, that
+// starts at row lo, so that parameter arrays of the chunk fit
+// into maxBatchBytes. The chunk has at least one row.
+func batchChunkEnd(rows [][]driver.Value, lo, chunk int) int {
+	hi := lo + chunk
+	if hi > len(rows) {
+		hi = len(rows)
+	}
+	var maxLen []int
+	for i := lo; i < hi; i++ {
+		if maxLen == nil {
+			maxLen = make([]int, len(rows[i]))
+		}
+		size := 0
+		for j, v := range rows[i] {
+			if l := batchValueLen(v); l > maxLen[j] {
+				maxLen[j] = l
+			}
+			size += maxLen[j]
+		}
+		if i > lo && size*(i-lo+1) > maxBatchBytes {
+			return i
+		}
+	}
+	return hi
+}
+
+// batchValueLen returns upper bound of bytes, that
+// newBatchColumn uses to store value v.
+func batchValueLen(v driver.Value) int {
+	switch d := v.(type) {
+	case string:
+		// UTF-16 has at most as many units as UTF-8 bytes.
+		return (len(d) + 1) * 2
+	case []byte:
+		return len(d)
+	case Decimal:
+		return len(d.String()) + 1
+	}
+	return 16
+}
+
+// batchValue converts v into value accepted by newBatchColumn.
+func (c *Conn) batchValue(v interface{}) (driver.Value, error) {
+	nv := driver.NamedValue{Value: v}
+	err := c.CheckNamedValue(&nv)
+	if err == driver.ErrSkip {
+		nv.Value, err = driver.DefaultParameterConverter.ConvertValue(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch d := nv.Value.(type) {
+	case sql.Out:
+		return nil, errors.New("output parameters are not supported")
+	case io.Reader:
+		return nil, errors.New("io.Reader parameters are not supported")
+	case int8:
+		return int64(d), nil
+	case int16:
+		return int64(d), nil
+	case int32:
+		return int64(d), nil
+	case uint8:
+		return int64(d), nil
+	case uint16:
+		return int64(d), nil
+	case uint32:
+		return int64(d), nil
+	case float32:
+		return float64(d), nil
+	case *big.Int:
+		if d == nil {
+			return nil, nil
+		}
+		return Decimal{text: d.String()}, nil
+	case *big.Float:
+		if d == nil {
+			return nil, nil
+		}
+		if d.IsInf() {
+			return nil, fmt.Errorf("cannot send %v *big.Float", d)
+		}
+		return Decimal{text: d.Text('f', -1)}, nil
+	}
+	return nv.Value, nil
+}
+
+// batchColumn holds values of one parameter for a chunk of
+// rows, bound as column-wise array of parameters.
+type batchColumn struct {
+	ctype   api.SQLSMALLINT
+	sqltype api.SQLSMALLINT
+	size    api.SQLULEN
+	decimal api.SQLSMALLINT
+	elemLen int // bytes used by one value in buf
+	buf     []byte
+	ind     []api.SQLLEN
+}
+
+func (c *batchColumn) elem(i int) unsafe.Pointer {
+	return unsafe.Pointer(&c.buf[i*c.elemLen])
+}
+
+// newBatchColumn prepares values of parameter p, idx-th
+// value of every row in rows, to be bound as an array.
+func newBatchColumn(rows [][]driver.Value, idx int, p *Parameter, conn *Conn) (*batchColumn, error) {
+	var first driver.Value
+	maxLen, maxInt, maxScale := 0, 0, 0
+	fitsInt32, fitsInt64 := true, true
+	for _, row := range rows {
+		v := row[idx]
+		if v == nil {
+			continue
+		}
+		if first == nil {
+			first = v
+		} else if reflect.TypeOf(v) != reflect.TypeOf(first) {
+			return nil, fmt.Errorf("odbc: batch parameter %d has values of types %T and %T", idx+1, first, v)
+		}
+		switch d := v.(type) {
+		case string:
+			l := len(d)
+			if conn.cfg.StringMode != StringNarrow {
+				l = len(api.StringToUTF16(d)) - 1
+			}
+			if l > maxLen {
+				maxLen = l
+			}
+		case []byte:
+			if len(d) > maxLen {
+				maxLen = len(d)
+			}
+		case int64:
+			if d < math.MinInt32 || d > math.MaxInt32 {
+				fitsInt32 = false
+			}
+		case uint64:
+			if d > math.MaxInt64 {
+				fitsInt64 = false
+			}
+		case Decimal:
+			precision, scale := d.precisionAndScale()
+			if precision-scale > maxInt {
+				maxInt = precision - scale
+			}
+			if scale > maxScale {
+				maxScale = scale
+			}
+			if len(d.String()) > maxLen {
+				maxLen = len(d.String())
+			}
+		}
+	}
+	c := &batchColumn{ind: make([]api.SQLLEN, len(rows))}
+	switch first.(type) {
+	case nil:
+		c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_WCHAR, api.SQL_WCHAR, 1, 2
+	case int64:
+		if fitsInt32 {
+			// Some ODBC drivers do not support SQL_BIGINT.
+			// See issue #78 for details.
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_LONG, api.SQL_INTEGER, 4, 4
+		} else {
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_SBIGINT, api.SQL_BIGINT, 8, 8
+		}
+	case uint64:
+		if fitsInt64 {
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_UBIGINT, api.SQL_BIGINT, 8, 8
+		} else {
+			// Too big for signed BIGINT.
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_UBIGINT, api.SQL_DECIMAL, 20, 8
+		}
+	case float64:
+		c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_DOUBLE, api.SQL_DOUBLE, 8, 8
+	case bool:
+		if conn.dialect.BoolAsInteger {
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_LONG, api.SQL_INTEGER, 4, 4
+		} else {
+			c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_BIT, api.SQL_BIT, 1, 1
+		}
+	case string:
+		c.size = api.SQLULEN(maxLen)
+		if c.size < 1 {
+			// size cannot be less then 1 even for empty fields
+			c.size = 1
+		}
+		if conn.cfg.StringMode == StringNarrow {
+			c.ctype, c.elemLen = api.SQL_C_CHAR, maxLen+1
+			switch {
+			case conn.dialect.LongStringParams, c.size >= 8000:
+				c.sqltype = api.SQL_LONGVARCHAR
+			case p.isDescribed:
+				c.sqltype = p.SQLType
+			default:
+				c.sqltype = api.SQL_VARCHAR
+			}
+		} else {
+			c.ctype, c.elemLen = api.SQL_C_WCHAR, (maxLen+1)*2
+			switch {
+			case conn.dialect.LongStringParams, c.size >= 4000:
+				c.sqltype = api.SQL_WLONGVARCHAR
+			case p.isDescribed:
+				c.sqltype = p.SQLType
+			default:
+				c.sqltype = api.SQL_WVARCHAR
+			}
+		}
+	case []byte:
+		c.size = api.SQLULEN(maxLen)
+		if c.size < 1 {
+			c.size = 1
+		}
+		c.ctype, c.elemLen = api.SQL_C_BINARY, int(c.size)
+		switch {
+		case c.size >= 8000:
+			c.sqltype = api.SQL_LONGVARBINARY
+		case p.isDescribed:
+			c.sqltype = p.SQLType
+		default:
+			c.sqltype = api.SQL_VARBINARY
+		}
+	case time.Time:
+		var v api.SQL_TIMESTAMP_STRUCT
+		c.ctype, c.sqltype, c.elemLen = api.SQL_C_TYPE_TIMESTAMP, api.SQL_TYPE_TIMESTAMP, int(unsafe.Sizeof(v))
+		if p.isDescribed && p.SQLType == api.SQL_TYPE_TIMESTAMP {
+			c.decimal = p.Decimal
+		}
+		if c.decimal <= 0 {
+			c.decimal = 3
+		}
+		c.size = 20 + api.SQLULEN(c.decimal)
+	case [16]byte:
+		var v api.SQLGUID
+		c.ctype, c.sqltype, c.size, c.elemLen = api.SQL_C_GUID, api.SQL_GUID, 16, int(unsafe.Sizeof(v))
+	case Decimal:
+		c.ctype, c.sqltype, c.elemLen = api.SQL_C_CHAR, api.SQL_DECIMAL, maxLen+1
+		c.size = api.SQLULEN(maxInt + maxScale)
+		c.decimal = api.SQLSMALLINT(maxScale)
+	default:
+		return nil, fmt.Errorf("odbc: unsupported batch parameter type %T", first)
+	}
+	c.buf = make([]byte, c.elemLen*len(rows))
+	for i, row := range rows {
+		c.ind[i] = api.SQLLEN(c.elemLen)
+		switch d := row[idx].(type) {
+		case nil:
+			c.ind[i] = api.SQL_NULL_DATA
+		case int64:
+			if c.ctype == api.SQL_C_LONG {
+				*(*int32)(c.elem(i)) = int32(d)
+			} else {
+				*(*int64)(c.elem(i)) = d
+			}
+		case uint64:
+			*(*uint64)(c.elem(i)) = d
+		case float64:
+			*(*float64)(c.elem(i)) = d
+		case bool:
+			var b byte
+			if d {
+				b = 1
+			}
+			if c.ctype == api.SQL_C_LONG {
+				*(*int32)(c.elem(i)) = int32(b)
+			} else {
+				c.buf[i] = b
+			}
+		case string:
+			if c.ctype == api.SQL_C_CHAR {
+				c.ind[i] = api.SQLLEN(copy(c.buf[i*c.elemLen:], d))
+				break
+			}
+			u := api.StringToUTF16(d)
+			u = u[:len(u)-1] // remove terminating 0
+			dst := (*[1 << 28]uint16)(c.elem(i))[:len(u):len(u)]
+			copy(dst, u)
+			c.ind[i] = api.SQLLEN(len(u) * 2)
+		case []byte:
+			c.ind[i] = api.SQLLEN(copy(c.buf[i*c.elemLen:(i+1)*c.elemLen], d))
+		case time.Time:
+			y, m, day := d.Date()
+			*(*api.SQL_TIMESTAMP_STRUCT)(c.elem(i)) = api.SQL_TIMESTAMP_STRUCT{
+				Year:     api.SQLSMALLINT(y),
+				Month:    api.SQLUSMALLINT(m),
+				Day:      api.SQLUSMALLINT(day),
+				Hour:     api.SQLUSMALLINT(d.Hour()),
+				Minute:   api.SQLUSMALLINT(d.Minute()),
+				Second:   api.SQLUSMALLINT(d.Second()),
+				Fraction: api.SQLUINTEGER(d.Nanosecond()),
+			}
+		case [16]byte:
+			*(*api.SQLGUID)(c.elem(i)) = guidFromBytes(d)
+		case Decimal:
+			c.ind[i] = api.SQLLEN(copy(c.buf[i*c.elemLen:], d.String()))
+		}
+	}
+	return c, nil
+}
+
+// execBatch executes s for rows, chunk rows at a time.
+func (s *ODBCStmt) execBatch(rows [][]driver.Value, chunk int, conn *Conn) (driver.Result, error) {
+	if chunk > 1 {
+		ret := api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_PARAMSET_SIZE, uintptr(chunk), api.SQL_IS_UINTEGER)
+		if IsError(ret) {
+			err := NewError("SQLSetStmtUIntPtrAttr", s.h)
+			if !isUnsupportedAttr(err) {
+				return nil, err
+			}
+			// Driver does not support arrays of
+			// parameters, execute rows one by one.
+			chunk = 1
+		}
+	}
+	status := make([]api.SQLUSMALLINT, chunk)
+	var processed api.SQLULEN
+	if chunk > 1 {
+		if err := s.setStmtPtrAttr(api.SQL_ATTR_PARAM_STATUS_PTR, unsafe.Pointer(&status[0])); err != nil {
+			return nil, err
+		}
+		if err := s.setStmtPtrAttr(api.SQL_ATTR_PARAMS_PROCESSED_PTR, unsafe.Pointer(&processed)); err != nil {
+			return nil, err
+		}
+		defer func() {
+			// Restore single row execution for Exec and Query.
+			api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_PARAMSET_SIZE, 1, api.SQL_IS_UINTEGER)
+			s.setStmtPtrAttr(api.SQL_ATTR_PARAM_STATUS_PTR, nil)
+			s.setStmtPtrAttr(api.SQL_ATTR_PARAMS_PROCESSED_PTR, nil)
+			runtime.KeepAlive(status)
+			runtime.KeepAlive(&processed)
+		}()
+	}
+	defer s.resetParams()
+
+	var total int64
+	for lo, hi := 0, 0; lo < len(rows); lo = hi {
+		hi = batchChunkEnd(rows, lo, chunk)
+		cols := make([]*batchColumn, len(s.Parameters))
+		for j := range s.Parameters {
+			c, err := newBatchColumn(rows[lo:hi], j, &s.Parameters[j], conn)
+			if err != nil {
+				return nil, err
+			}
+			ret := api.SQLBindParameter(s.h, api.SQLUSMALLINT(j+1),
+				api.SQL_PARAM_INPUT, c.ctype, c.sqltype, c.size, c.decimal,
+				api.SQLPOINTER(unsafe.Pointer(&c.buf[0])), api.SQLLEN(c.elemLen), &c.ind[0])
+			if IsError(ret) {
+				return nil, NewError("SQLBindParameter", s.h)
+			}
+			cols[j] = c
+		}
+		if chunk > 1 {
+			ret := api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_PARAMSET_SIZE, uintptr(hi-lo), api.SQL_IS_UINTEGER)
+			if IsError(ret) {
+				return nil, NewError("SQLSetStmtUIntPtrAttr", s.h)
+			}
+			processed = 0
+			for i := range status {
+				status[i] = api.SQL_PARAM_UNUSED
+			}
+		}
+		ret := api.SQLExecute(s.h)
+		runtime.KeepAlive(cols)
+		var failed []int
+		n := hi - lo
+		if chunk > 1 {
+			if processed > 0 {
+				n = int(processed)
+			}
+			for i := 0; i < n; i++ {
+				if status[i] == api.SQL_PARAM_ERROR {
+					failed = append(failed, lo+i)
+				}
+			}
+		} else if IsError(ret) {
+			failed = []int{lo}
+		}
+		if IsError(ret) || len(failed) > 0 {
+			return nil, &BatchError{
+				Failed:       failed,
+				Processed:    lo + n,
+				RowsAffected: total,
+				Err:          NewError("SQLExecute", s.h),
+			}
+		}
+		c, err := s.rowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		total += c
+	}
+	return &Result{rowCount: total}, nil
+}
+
+// resetParams unbinds parameters of s, that execBatch bound to
+// its arrays, so following Exec binds them again.
+func (s *ODBCStmt) resetParams() {
+	api.SQLFreeStmt(s.h, api.SQL_RESET_PARAMS)
+	for i := range s.Parameters {
+		s.Parameters[i].bound = paramBinding{}
+	}
+}
@@ -0,0 +1,134 @@
+package odbc
+
+import (
+	"database/sql/driver"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+func TestBatchChunkSize(t *testing.T) {
+	tests := []struct {
+		batchSize, numParams, want int
+	}{
+		{0, 2, defaultBatchSize},
+		{10, 2, 10},
+		{100000, 1, maxBatchParams},
+		{1000, 100, maxBatchParams / 100},
+		{1000, 100000, 1},
+	}
+	for _, test := range tests {
+		if got := batchChunkSize(test.batchSize, test.numParams); got != test.want {
+			t.Errorf("batchChunkSize(%d, %d): want %d, got %d", test.batchSize, test.numParams, test.want, got)
+		}
+	}
+}
+
+func TestBatchChunkEnd(t *testing.T) {
+	rows := [][]driver.Value{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}, {int64(4), "d"}, {nil, "e"}}
+	if got := batchChunkEnd(rows, 0, 10); got != 5 {
+		t.Errorf("short values: want 5, got %d", got)
+	}
+	if got := batchChunkEnd(rows, 0, 2); got != 2 {
+		t.Errorf("chunk 2: want 2, got %d", got)
+	}
+	// Every row of the chunk takes space of the longest value.
+	rows[2][1] = strings.Repeat("x", maxBatchBytes/8)
+	if got := batchChunkEnd(rows, 0, 10); got != 3 {
+		t.Errorf("long value: want 3, got %d", got)
+	}
+	if got := batchChunkEnd(rows, 3, 10); got != 5 {
+		t.Errorf("after long value: want 5, got %d", got)
+	}
+	rows[0][1] = strings.Repeat("x", maxBatchBytes)
+	if got := batchChunkEnd(rows, 0, 10); got != 1 {
+		t.Errorf("huge value: want 1, got %d", got)
+	}
+}
+
+func TestBatchValue(t *testing.T) {
+	c := &Conn{}
+	tests := []struct {
+		v    interface{}
+		want driver.Value
+	}{
+		{int8(-1), int64(-1)},
+		{uint32(7), int64(7)},
+		{uint64(1 << 63), uint64(1 << 63)},
+		{float32(0.5), float64(0.5)},
+		{big.NewInt(-3), Decimal{text: "-3"}},
+		{(*big.Int)(nil), nil},
+		{7, int64(7)},
+		{"s", "s"},
+	}
+	for _, test := range tests {
+		got, err := c.batchValue(test.v)
+		if err != nil {
+			t.Errorf("batchValue(%T) failed: %v", test.v, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("batchValue(%T): want %#v, got %#v", test.v, test.want, got)
+		}
+	}
+	if _, err := c.batchValue(struct{}{}); err == nil {
+		t.Error("batchValue(struct{}{}) should fail")
+	}
+	if _, err := c.batchValue(strings.NewReader("s")); err == nil {
+		t.Error("batchValue(io.Reader) should fail")
+	}
+}
+
+func TestNewBatchColumn(t *testing.T) {
+	conn := &Conn{cfg: &Config{}, dialect: DefaultDialect}
+	rows := [][]driver.Value{
+		{int64(1), "ab", nil},
+		{nil, "abcd", nil},
+		{int64(1 << 40), "", nil},
+	}
+	c, err := newBatchColumn(rows, 0, &Parameter{}, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ctype != api.SQL_C_SBIGINT || c.elemLen != 8 || c.ind[1] != api.SQL_NULL_DATA {
+		t.Errorf("unexpected int64 column %+v", c)
+	}
+	if *(*int64)(c.elem(2)) != 1<<40 {
+		t.Errorf("unexpected value %d", *(*int64)(c.elem(2)))
+	}
+	c, err = newBatchColumn(rows, 1, &Parameter{}, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ctype != api.SQL_C_WCHAR || c.size != 4 || c.elemLen != 10 {
+		t.Errorf("unexpected string column %+v", c)
+	}
+	if c.ind[0] != 4 || c.ind[1] != 8 || c.ind[2] != 0 {
+		t.Errorf("unexpected string lengths %v", c.ind)
+	}
+	c, err = newBatchColumn(rows, 2, &Parameter{}, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ind := range c.ind {
+		if ind != api.SQL_NULL_DATA {
+			t.Errorf("row %d: expected NULL, got %d", i, ind)
+		}
+	}
+
+	rows = [][]driver.Value{{uint64(1)}, {uint64(1 << 63)}}
+	c, err = newBatchColumn(rows, 0, &Parameter{}, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ctype != api.SQL_C_UBIGINT || c.sqltype != api.SQL_DECIMAL || *(*uint64)(c.elem(1)) != 1<<63 {
+		t.Errorf("unexpected uint64 column %+v", c)
+	}
+
+	rows = [][]driver.Value{{int64(1)}, {"x"}}
+	if _, err := newBatchColumn(rows, 0, &Parameter{}, conn); err == nil {
+		t.Error("newBatchColumn should fail for mixed types")
+	}
+}
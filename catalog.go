@@ -0,0 +1,166 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"context"
+	"database/sql/driver"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// Catalog functions below return result sets described in ODBC
+// documentation of the matching API call (for example, SQLTables).
+// They are meant for use with sql.Conn.Raw. Empty string arguments
+// are passed as NULL, that is, they do not restrict the result.
+// Names accept search patterns where ODBC allows them.
+
+// catalogArg converts catalog function argument s into
+// ODBC string pointer and length.
+func catalogArg(s string) (*api.SQLWCHAR, api.SQLSMALLINT) {
+	if s == "" {
+		return nil, 0
+	}
+	b := api.StringToUTF16(s)
+	return (*api.SQLWCHAR)(unsafe.Pointer(&b[0])), api.SQL_NTS
+}
+
+// catalogRows runs catalog function call on new statement
+// and returns its result set.
+func (c *Conn) catalogRows(ctx context.Context, apiName string, call func(h api.SQLHSTMT) api.SQLRETURN) (driver.Rows, error) {
+	if c.bad {
+		return nil, driver.ErrBadConn
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	h, err := c.allocStmtHandle()
+	if err != nil {
+		return nil, err
+	}
+	os := &ODBCStmt{
+		h:          h,
+		conn:       c,
+		loc:        c.loc,
+		usedByRows: true,
+	}
+	c.addStmt(os)
+	if err := os.setQueryTimeout(ctx, c.cfg.QueryTimeout); err != nil {
+		os.closeByRows()
+		return nil, err
+	}
+	stop := os.watchCancel(ctx)
+	ret := call(h)
+	if IsError(ret) {
+		stop()
+		err := c.newError(apiName, h)
+		os.closeByRows()
+		if cerr := ctx.Err(); cerr != nil {
+			return nil, cerr
+		}
+		return nil, err
+	}
+	if err := os.BindColumns(); err != nil {
+		stop()
+		os.closeByRows()
+		return nil, err
+	}
+	return &Rows{os: os, ctx: ctx, stopWatch: stop}, nil
+}
+
+// Tables returns tables, views and other objects of types tableType
+// (comma separated list, for example "TABLE,VIEW"). See SQLTables.
+func (c *Conn) Tables(ctx context.Context, catalog, schema, table, tableType string) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	tab, tablen := catalogArg(table)
+	typ, typlen := catalogArg(tableType)
+	return c.catalogRows(ctx, "SQLTables", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLTables(h, cat, catlen, sch, schlen, tab, tablen, typ, typlen)
+	})
+}
+
+// Columns returns columns of matching tables. See SQLColumns.
+func (c *Conn) Columns(ctx context.Context, catalog, schema, table, column string) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	tab, tablen := catalogArg(table)
+	col, collen := catalogArg(column)
+	return c.catalogRows(ctx, "SQLColumns", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLColumns(h, cat, catlen, sch, schlen, tab, tablen, col, collen)
+	})
+}
+
+// PrimaryKeys returns primary key columns of table. See SQLPrimaryKeys.
+func (c *Conn) PrimaryKeys(ctx context.Context, catalog, schema, table string) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	tab, tablen := catalogArg(table)
+	return c.catalogRows(ctx, "SQLPrimaryKeys", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLPrimaryKeys(h, cat, catlen, sch, schlen, tab, tablen)
+	})
+}
+
+// ForeignKeys returns foreign keys that refer to primary key of table
+// pkTable, or foreign keys of table fkTable, or, if both are set, foreign
+// keys of fkTable that refer to pkTable. See SQLForeignKeys.
+func (c *Conn) ForeignKeys(ctx context.Context, pkCatalog, pkSchema, pkTable, fkCatalog, fkSchema, fkTable string) (driver.Rows, error) {
+	pkcat, pkcatlen := catalogArg(pkCatalog)
+	pksch, pkschlen := catalogArg(pkSchema)
+	pktab, pktablen := catalogArg(pkTable)
+	fkcat, fkcatlen := catalogArg(fkCatalog)
+	fksch, fkschlen := catalogArg(fkSchema)
+	fktab, fktablen := catalogArg(fkTable)
+	return c.catalogRows(ctx, "SQLForeignKeys", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLForeignKeys(h, pkcat, pkcatlen, pksch, pkschlen, pktab, pktablen,
+			fkcat, fkcatlen, fksch, fkschlen, fktab, fktablen)
+	})
+}
+
+// Statistics returns statistics and indexes of table. If unique is
+// true, only unique indexes are returned. See SQLStatistics.
+func (c *Conn) Statistics(ctx context.Context, catalog, schema, table string, unique bool) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	tab, tablen := catalogArg(table)
+	var u api.SQLUSMALLINT = api.SQL_INDEX_ALL
+	if unique {
+		u = api.SQL_INDEX_UNIQUE
+	}
+	return c.catalogRows(ctx, "SQLStatistics", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLStatistics(h, cat, catlen, sch, schlen, tab, tablen, u, api.SQL_QUICK)
+	})
+}
+
+// Procedures returns stored procedures. See SQLProcedures.
+func (c *Conn) Procedures(ctx context.Context, catalog, schema, proc string) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	p, plen := catalogArg(proc)
+	return c.catalogRows(ctx, "SQLProcedures", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLProcedures(h, cat, catlen, sch, schlen, p, plen)
+	})
+}
+
+// ProcedureColumns returns parameters and result columns of stored
+// procedures. See SQLProcedureColumns.
+func (c *Conn) ProcedureColumns(ctx context.Context, catalog, schema, proc, column string) (driver.Rows, error) {
+	cat, catlen := catalogArg(catalog)
+	sch, schlen := catalogArg(schema)
+	p, plen := catalogArg(proc)
+	col, collen := catalogArg(column)
+	return c.catalogRows(ctx, "SQLProcedureColumns", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLProcedureColumns(h, cat, catlen, sch, schlen, p, plen, col, collen)
+	})
+}
+
+// TypeInfo returns data types supported by the data source. Use
+// api.SQL_ALL_TYPES to get all of them. See SQLGetTypeInfo.
+func (c *Conn) TypeInfo(ctx context.Context, sqlType api.SQLSMALLINT) (driver.Rows, error) {
+	return c.catalogRows(ctx, "SQLGetTypeInfo", func(h api.SQLHSTMT) api.SQLRETURN {
+		return api.SQLGetTypeInfo(h, sqlType)
+	})
+}
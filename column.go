@@ -42,24 +42,28 @@ type Column interface {
 	ScanType() reflect.Type
 }
 
-func describeColumn(h api.SQLHSTMT, idx int, namebuf []uint16) (namelen int, sqltype api.SQLSMALLINT, size api.SQLULEN, nullable api.SQLSMALLINT, ret api.SQLRETURN) {
-	var l, decimal api.SQLSMALLINT
+func describeColumn(h api.SQLHSTMT, idx int, namebuf []uint16) (namelen int, sqltype api.SQLSMALLINT, size api.SQLULEN, decimal, nullable api.SQLSMALLINT, ret api.SQLRETURN) {
+	var l api.SQLSMALLINT
 	ret = api.SQLDescribeCol(h, api.SQLUSMALLINT(idx+1),
 		(*api.SQLWCHAR)(unsafe.Pointer(&namebuf[0])),
 		api.SQLSMALLINT(len(namebuf)), &l,
 		&sqltype, &size, &decimal, &nullable)
-	return int(l), sqltype, size, nullable, ret
+	return int(l), sqltype, size, decimal, nullable, ret
 }
 
 // TODO(brainman): did not check for MS SQL timestamp
 
 func NewColumn(h api.SQLHSTMT, idx int, loc *time.Location) (Column, error) {
+	return newColumn(h, idx, loc, DefaultDialect, DecimalExact)
+}
+
+func newColumn(h api.SQLHSTMT, idx int, loc *time.Location, dialect *Dialect, decimals DecimalMode) (Column, error) {
 	namebuf := make([]uint16, 150)
-	namelen, sqltype, size, nullable, ret := describeColumn(h, idx, namebuf)
+	namelen, sqltype, size, decimal, nullable, ret := describeColumn(h, idx, namebuf)
 	if ret == api.SQL_SUCCESS_WITH_INFO && namelen > len(namebuf) {
 		// try again with bigger buffer
 		namebuf = make([]uint16, namelen)
-		namelen, sqltype, size, nullable, ret = describeColumn(h, idx, namebuf)
+		namelen, sqltype, size, decimal, nullable, ret = describeColumn(h, idx, namebuf)
 	}
 	if IsError(ret) {
 		return nil, NewError("SQLDescribeCol", h)
@@ -73,6 +77,8 @@ func NewColumn(h api.SQLHSTMT, idx int, loc *time.Location) (Column, error) {
 		loc:      loc,
 		SQLType:  sqltype,
 		nullable: nullable,
+		size:     size,
+		decimal:  decimal,
 	}
 	switch sqltype {
 	case api.SQL_BIT:
@@ -81,7 +87,18 @@ func NewColumn(h api.SQLHSTMT, idx int, loc *time.Location) (Column, error) {
 		return NewBindableColumn(b, api.SQL_C_LONG, 4), nil
 	case api.SQL_BIGINT:
 		return NewBindableColumn(b, api.SQL_C_SBIGINT, 8), nil
-	case api.SQL_NUMERIC, api.SQL_DECIMAL, api.SQL_FLOAT, api.SQL_REAL, api.SQL_DOUBLE:
+	case api.SQL_NUMERIC, api.SQL_DECIMAL:
+		if decimals == DecimalFloat {
+			return NewBindableColumn(b, api.SQL_C_DOUBLE, 8), nil
+		}
+		// Fetch as text, size is precision. Leave room for
+		// sign, decimal point and leading zero. Columns of
+		// unknown precision are read with SQLGetData.
+		if size > 0 {
+			size += 3
+		}
+		return NewVariableWidthColumn(b, api.SQL_C_CHAR, size)
+	case api.SQL_FLOAT, api.SQL_REAL, api.SQL_DOUBLE:
 		return NewBindableColumn(b, api.SQL_C_DOUBLE, 8), nil
 	case api.SQL_TYPE_TIMESTAMP:
 		var v api.SQL_TIMESTAMP_STRUCT
@@ -105,15 +122,31 @@ func NewColumn(h api.SQLHSTMT, idx int, loc *time.Location) (Column, error) {
 	case api.SQL_BINARY:
 		return NewVariableWidthColumn(b, api.SQL_C_BINARY, size)
 	// (eric) SIG-19527 As a workaround for a Databricks ODBC driver bug, we treat
-	// SQL_(W)VARCHAR as SQL_(W)LONGVARCHAR (and SQL_VARBINARY as SQL_LONGVARBINARY).
+	// SQL_(W)VARCHAR as SQL_(W)LONGVARCHAR (and SQL_VARBINARY as SQL_LONGVARBINARY),
+	// unless the dialect trusts column sizes.
 	// Specifically, the Simbaspark ODBC driver's SQLDescribeCol API always returns
 	// 256 bytes for the size of a VARCHAR column, regardless of its actual size. As
 	// such, we're effectively ignoring that value.
-	case api.SQL_LONGVARCHAR, api.SQL_VARCHAR:
+	case api.SQL_VARCHAR:
+		if dialect.TrustColumnSize {
+			return NewVariableWidthColumn(b, api.SQL_C_CHAR, size)
+		}
+		return NewVariableWidthColumn(b, api.SQL_C_CHAR, 0)
+	case api.SQL_WVARCHAR:
+		if dialect.TrustColumnSize {
+			return NewVariableWidthColumn(b, api.SQL_C_WCHAR, size)
+		}
+		return NewVariableWidthColumn(b, api.SQL_C_WCHAR, 0)
+	case api.SQL_VARBINARY:
+		if dialect.TrustColumnSize {
+			return NewVariableWidthColumn(b, api.SQL_C_BINARY, size)
+		}
+		return NewVariableWidthColumn(b, api.SQL_C_BINARY, 0)
+	case api.SQL_LONGVARCHAR:
 		return NewVariableWidthColumn(b, api.SQL_C_CHAR, 0)
-	case api.SQL_WLONGVARCHAR, api.SQL_WVARCHAR, api.SQL_SS_XML:
+	case api.SQL_WLONGVARCHAR, api.SQL_SS_XML:
 		return NewVariableWidthColumn(b, api.SQL_C_WCHAR, 0)
-	case api.SQL_LONGVARBINARY, api.SQL_VARBINARY:
+	case api.SQL_LONGVARBINARY:
 		return NewVariableWidthColumn(b, api.SQL_C_BINARY, 0)
 	default:
 		return nil, fmt.Errorf("unsupported column type %d", sqltype)
@@ -127,6 +160,10 @@ type BaseColumn struct {
 	SQLType  api.SQLSMALLINT
 	CType    api.SQLSMALLINT
 	nullable api.SQLSMALLINT
+	// size and decimal are column size and decimal
+	// digits, as returned by SQLDescribeCol.
+	size    api.SQLULEN
+	decimal api.SQLSMALLINT
 }
 
 func (c *BaseColumn) Name() string {
@@ -155,6 +192,9 @@ func (c *BaseColumn) Value(buf []byte) (driver.Value, error) {
 	case api.SQL_C_DOUBLE:
 		return *((*float64)(p)), nil
 	case api.SQL_C_CHAR:
+		if c.SQLType == api.SQL_NUMERIC || c.SQLType == api.SQL_DECIMAL {
+			return normalizeDecimal(string(buf)), nil
+		}
 		return buf, nil
 	case api.SQL_C_WCHAR:
 		if p == nil {
@@ -247,10 +287,11 @@ func (c *BaseColumn) ScanType() reflect.Type {
 		return reflect.TypeOf(int32(0))
 	case api.SQL_BIGINT:
 		return reflect.TypeOf(int64(0))
-	case api.SQL_NUMERIC:
-		return reflect.TypeOf([]byte{})
-	case api.SQL_DECIMAL:
-		return reflect.TypeOf([]byte{})
+	case api.SQL_NUMERIC, api.SQL_DECIMAL:
+		if c.CType == api.SQL_C_DOUBLE {
+			return reflect.TypeOf(float64(0))
+		}
+		return reflect.TypeOf(Decimal{})
 	case -25: // not declared in sql.h nor in sqlext.h
 		return reflect.TypeOf(int64(0))
 	// NUMERIC NOT FIXED LENGTH
@@ -290,6 +331,11 @@ type BindableColumn struct {
 	Size            int
 	Len             BufferLen
 	Buffer          []byte
+	// Lens and stride are used, if column is bound to an array
+	// of rows (block cursor). Value of row i is then stored at
+	// Buffer[i*stride:] and its length in Lens[i].
+	Lens   []BufferLen
+	stride int
 }
 
 // TODO(brainman): BindableColumn.Buffer is used by external code after external code returns - that needs to be avoided in the future
@@ -305,8 +351,13 @@ func NewBindableColumn(b *BaseColumn, ctype api.SQLSMALLINT, bufSize int) *Binda
 	return c
 }
 
+// maxBindableWidth is the widest column, in characters or bytes,
+// that is bound to a buffer. Wider columns, and columns of unknown
+// width, are read with SQLGetData.
+const maxBindableWidth = 1024
+
 func NewVariableWidthColumn(b *BaseColumn, ctype api.SQLSMALLINT, colWidth api.SQLULEN) (Column, error) {
-	if colWidth == 0 || colWidth > 1024 {
+	if colWidth == 0 || colWidth > maxBindableWidth {
 		b.CType = ctype
 		return &NonBindableColumn{b}, nil
 	}
@@ -336,7 +387,61 @@ func (c *BindableColumn) Bind(h api.SQLHSTMT, idx int) (bool, error) {
 	return true, nil
 }
 
+// bindArray binds c to buffers that hold n rows.
+func (c *BindableColumn) bindArray(h api.SQLHSTMT, idx int, n int) error {
+	c.stride = 8 // same as NewBindableColumn
+	if c.Size > c.stride {
+		c.stride = c.Size
+	}
+	c.Buffer = make([]byte, c.stride*n)
+	c.Lens = make([]BufferLen, n)
+	ret := api.SQLBindCol(h, api.SQLUSMALLINT(idx+1), c.CType,
+		api.SQLPOINTER(unsafe.Pointer(&c.Buffer[0])), api.SQLLEN(c.stride),
+		(*api.SQLLEN)(&c.Lens[0]))
+	if IsError(ret) {
+		return NewError("SQLBindCol", h)
+	}
+	c.IsBound = true
+	return nil
+}
+
+// rowValue returns value of c in row i of the fetched rowset.
+func (c *BindableColumn) rowValue(idx, i int) (driver.Value, error) {
+	b, err := c.rawRowValue(idx, i)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return c.BaseColumn.Value(b)
+}
+
+// rawRowValue returns data of c in row i of the fetched
+// rowset, as stored by the driver, or nil, if it is NULL.
+func (c *BindableColumn) rawRowValue(idx, i int) ([]byte, error) {
+	l := c.Lens[i]
+	if l.IsNull() {
+		return nil, nil
+	}
+	if !c.IsVariableWidth && int(l) != c.Size {
+		return nil, fmt.Errorf("wrong column #%d length %d returned, %d expected", idx, l, c.Size)
+	}
+	if int(l) > c.stride {
+		return nil, fmt.Errorf("column #%d value is truncated: %d bytes returned, but buffer size is %d", idx, l, c.stride)
+	}
+	b := c.Buffer[i*c.stride:]
+	return b[:l:l], nil
+}
+
 func (c *BindableColumn) Value(h api.SQLHSTMT, idx int) (driver.Value, error) {
+	b, err := c.rawValue(h, idx)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return c.BaseColumn.Value(b)
+}
+
+// rawValue returns data of c in current row, as stored by the
+// driver, or nil, if it is NULL.
+func (c *BindableColumn) rawValue(h api.SQLHSTMT, idx int) ([]byte, error) {
 	if !c.IsBound {
 		ret := c.Len.GetData(h, idx, c.CType, c.Buffer)
 		if IsError(ret) {
@@ -350,7 +455,7 @@ func (c *BindableColumn) Value(h api.SQLHSTMT, idx int) (driver.Value, error) {
 	if !c.IsVariableWidth && int(c.Len) != c.Size {
 		return nil, fmt.Errorf("wrong column #%d length %d returned, %d expected", idx, c.Len, c.Size)
 	}
-	return c.BaseColumn.Value(c.Buffer[:c.Len])
+	return c.Buffer[:c.Len], nil
 }
 
 // NonBindableColumn provide access to columns, that can't be bound.
@@ -365,8 +470,18 @@ func (c *NonBindableColumn) Bind(h api.SQLHSTMT, idx int) (bool, error) {
 }
 
 func (c *NonBindableColumn) Value(h api.SQLHSTMT, idx int) (driver.Value, error) {
+	total, err := c.rawValue(h, idx)
+	if err != nil || total == nil {
+		return nil, err
+	}
+	return c.BaseColumn.Value(total)
+}
+
+// rawValue reads data of c in current row with SQLGetData. It
+// returns nil, if value is NULL.
+func (c *NonBindableColumn) rawValue(h api.SQLHSTMT, idx int) ([]byte, error) {
 	var l BufferLen
-	var total []byte
+	total := []byte{}
 	b := make([]byte, 1024)
 loop:
 	for {
@@ -383,8 +498,8 @@ loop:
 			total = append(total, b[:l]...)
 			break loop
 		case api.SQL_SUCCESS_WITH_INFO:
-			err := NewError("SQLGetData", h).(*Error)
-			if len(err.Diag) > 0 && err.Diag[0].State != "01004" {
+			err := NewError("SQLGetData", h)
+			if e, ok := err.(*Error); !ok || len(e.Diag) > 0 && e.Diag[0].State != "01004" {
 				return nil, err
 			}
 			i := len(b)
@@ -409,5 +524,5 @@ loop:
 			return nil, NewError("SQLGetData", h)
 		}
 	}
-	return c.BaseColumn.Value(total)
+	return total, nil
 }
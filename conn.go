@@ -7,7 +7,7 @@ package odbc
 import (
 	"context"
 	"database/sql/driver"
-	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -15,53 +15,79 @@ import (
 )
 
 type Conn struct {
-	h                api.SQLHDBC
-	tx               *Tx
-	bad              bool
-	isMSAccessDriver bool
-	loc              *time.Location
+	h       api.SQLHDBC
+	drv     *Driver
+	tx      *Tx
+	bad     bool
+	loc     *time.Location
+	cfg     *Config
+	dbms    DBMSInfo
+	dialect *Dialect
+	// scrollOptions are SQL_SCROLL_OPTIONS of the driver,
+	// read on connect, if cfg.CursorType is scrollable.
+	scrollOptions uint32
+	// mu protects stmts.
+	mu    sync.Mutex
+	stmts map[*ODBCStmt]struct{} // statements allocated in h
 }
 
-var accessDriverSubstr = strings.ToUpper(strings.Replace("DRIVER={Microsoft Access Driver", " ", "", -1))
-
 func (d *Driver) Open(dsn string) (driver.Conn, error) {
-	if d.initErr != nil {
-		return nil, d.initErr
-	}
-
-	loc, err := extractTimezoneFromDsn(dsn)
+	c, err := d.OpenConnector(dsn)
 	if err != nil {
 		return nil, err
 	}
+	return c.Connect(context.Background())
+}
 
-	var out api.SQLHANDLE
-	ret := api.SQLAllocHandle(api.SQL_HANDLE_DBC, api.SQLHANDLE(d.h), &out)
-	if IsError(ret) {
-		return nil, NewError("SQLAllocHandle", d.h)
+func (c *Conn) addStmt(s *ODBCStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stmts == nil {
+		c.stmts = make(map[*ODBCStmt]struct{})
 	}
-	h := api.SQLHDBC(out)
-	drv.Stats.updateHandleCount(api.SQL_HANDLE_DBC, 1)
+	c.stmts[s] = struct{}{}
+}
 
-	b := api.StringToUTF16(dsn)
-	ret = api.SQLDriverConnect(h, 0,
-		(*api.SQLWCHAR)(unsafe.Pointer(&b[0])), api.SQL_NTS,
-		nil, 0, nil, api.SQL_DRIVER_NOPROMPT)
-	if IsError(ret) {
-		defer releaseHandle(h)
-		return nil, NewError("SQLDriverConnect", h)
+func (c *Conn) removeStmt(s *ODBCStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.stmts, s)
+}
+
+// closeStmts frees all statements still open on c,
+// regardless of Stmt or Rows using them.
+func (c *Conn) closeStmts() error {
+	c.mu.Lock()
+	stmts := make([]*ODBCStmt, 0, len(c.stmts))
+	for s := range c.stmts {
+		stmts = append(stmts, s)
 	}
-	isAccess := strings.Contains(strings.ToUpper(strings.Replace(dsn, " ", "", -1)), accessDriverSubstr)
-	return &Conn{h: h, isMSAccessDriver: isAccess, loc: loc}, nil
+	c.mu.Unlock()
+
+	var err error
+	for _, s := range stmts {
+		if e := s.closeAll(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
 }
 
+// Close frees statements that are still open, disconnects
+// and frees connection handle.
 func (c *Conn) Close() (err error) {
+	if c.h == api.SQLHDBC(api.SQL_NULL_HDBC) {
+		return nil
+	}
 	if c.tx != nil {
 		c.tx.Rollback()
 	}
+	err = c.closeStmts()
 	h := c.h
 	defer func() {
 		c.h = api.SQLHDBC(api.SQL_NULL_HDBC)
-		e := releaseHandle(h)
+		c.drv.removeConn(c)
+		e := c.drv.releaseHandle(h)
 		if err == nil {
 			err = e
 		}
@@ -89,3 +115,109 @@ func (c *Conn) ResetSession(_ context.Context) error {
 	}
 	return nil
 }
+
+// isDead reports whether the driver knows that connection
+// to the server is lost. It does not talk to the server.
+// Drivers that do not support SQL_ATTR_CONNECTION_DEAD
+// are assumed to be alive.
+func (c *Conn) isDead() bool {
+	v, err := c.getConnectAttr(api.SQL_ATTR_CONNECTION_DEAD)
+	if err == driver.ErrBadConn {
+		return true
+	}
+	return err == nil && v == api.SQL_CD_TRUE
+}
+
+// Ping implements driver.Pinger. It checks SQL_ATTR_CONNECTION_DEAD
+// and then runs Config.ValidationQuery, if any.
+func (c *Conn) Ping(ctx context.Context) error {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+	if c.isDead() {
+		c.bad = true
+		return driver.ErrBadConn
+	}
+	if c.cfg.ValidationQuery == "" {
+		return nil
+	}
+	os, err := c.prepareODBCStmt(c.cfg.ValidationQuery, CursorForwardOnly)
+	if err != nil {
+		return err
+	}
+	defer os.closeByStmt()
+	if err := os.setQueryTimeout(ctx, c.cfg.QueryTimeout); err != nil {
+		return err
+	}
+	stop := os.watchCancel(ctx)
+	err = os.Exec(nil, c)
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if c.isDead() {
+			c.bad = true
+			return driver.ErrBadConn
+		}
+		return err
+	}
+	return nil
+}
+
+// IsValid implements driver.Validator. database/sql calls it
+// before returning connection to the pool, and discards
+// connections that are marked bad or that the server dropped.
+func (c *Conn) IsValid() bool {
+	return !c.bad && !c.isDead()
+}
+
+// execDirect executes query on c and discards any results.
+func (c *Conn) execDirect(query string) error {
+	os, err := c.prepareODBCStmt(query, CursorForwardOnly)
+	if err != nil {
+		return err
+	}
+	defer os.closeByStmt()
+	return os.Exec(nil, c)
+}
+
+// getConnectAttr returns value of integer connection attribute attr.
+func (c *Conn) getConnectAttr(attr api.SQLINTEGER) (uintptr, error) {
+	var v uintptr
+	ret := api.SQLGetConnectAttr(c.h, attr, api.SQLPOINTER(unsafe.Pointer(&v)), api.SQL_IS_UINTEGER, nil)
+	if IsError(ret) {
+		return 0, c.newError("SQLGetConnectAttr", c.h)
+	}
+	return v, nil
+}
+
+// InfoUint32 returns value of SQLUINTEGER information type t,
+// for example api.SQL_GETDATA_EXTENSIONS.
+func (c *Conn) InfoUint32(t api.SQLUSMALLINT) (uint32, error) {
+	v, ret := api.SQLGetInfoUInteger(c.h, t)
+	if IsError(ret) {
+		return 0, c.newError("SQLGetInfo", c.h)
+	}
+	return uint32(v), nil
+}
+
+// InfoUint16 returns value of SQLUSMALLINT information type t,
+// for example api.SQL_MAX_COLUMN_NAME_LEN.
+func (c *Conn) InfoUint16(t api.SQLUSMALLINT) (uint16, error) {
+	v, ret := api.SQLGetInfoUSmallInt(c.h, t)
+	if IsError(ret) {
+		return 0, c.newError("SQLGetInfo", c.h)
+	}
+	return uint16(v), nil
+}
+
+// InfoString returns value of character string information type t,
+// for example api.SQL_IDENTIFIER_QUOTE_CHAR.
+func (c *Conn) InfoString(t api.SQLUSMALLINT) (string, error) {
+	v, ret := api.SQLGetInfoString(c.h, t)
+	if IsError(ret) {
+		return "", c.newError("SQLGetInfo", c.h)
+	}
+	return v, nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// Config describes connections opened by a Connector.
+type Config struct {
+	// DSN is the connection string passed to SQLDriverConnect.
+	DSN string
+	// Location is used for date and time values returned by the
+	// database. If nil, Driver.Loc is used, and UTC if that is nil too.
+	Location *time.Location
+	// ConnAttrs are set on every connection handle before it connects.
+	ConnAttrs []ConnAttr
+	// LoginTimeout and ConnectionTimeout set SQL_ATTR_LOGIN_TIMEOUT and
+	// SQL_ATTR_CONNECTION_TIMEOUT, rounded up to whole seconds.
+	// Zero leaves the driver default in place. A deadline of the
+	// context passed to Connect shortens the login timeout, but not
+	// the connection timeout, which applies for the connection lifetime.
+	LoginTimeout      time.Duration
+	ConnectionTimeout time.Duration
+	// QueryTimeout limits how long a statement may run. It is set as
+	// SQL_ATTR_QUERY_TIMEOUT, so the server enforces it. A context
+	// deadline that expires sooner takes precedence. Zero means no limit.
+	QueryTimeout time.Duration
+	// InitStatements are executed, in order, on every new connection.
+	InitStatements []string
+	// FetchSize is the number of rows fetched from the driver at once
+	// (SQL_ATTR_ROW_ARRAY_SIZE). Zero means 100, one disables block
+	// fetching. Result sets with long columns, that have to be read
+	// with SQLGetData, are always fetched one row at a time. So are
+	// VARCHAR and VARBINARY columns, unless the dialect has
+	// TrustColumnSize set, as built-in dialects other than
+	// "access" and "default" do.
+	FetchSize int
+	// BatchSize is the maximum number of rows Stmt.ExecBatch
+	// sends with one SQLExecute. Zero means 1000.
+	BatchSize int
+	// StringMode selects how string parameters are bound.
+	StringMode StringMode
+	// DecimalMode selects how DECIMAL and NUMERIC columns are fetched.
+	DecimalMode DecimalMode
+	// CursorType selects cursor of statements executed with Query.
+	// Exec always uses forward-only cursor. Scrollable cursors are
+	// moved with Rows.First, Last, Prior, Absolute and Relative, and
+	// fetch one row at a time. Query fails, if the driver does not
+	// support the cursor type.
+	CursorType CursorType
+	// Dialect, if set, is used instead of the dialect
+	// picked by the connected DBMS name.
+	Dialect *Dialect
+	// ValidationQuery, if set, is executed by Ping to make sure
+	// the server still serves the connection, for example "select 1".
+	ValidationQuery string
+}
+
+// ConnAttr is an integer connection attribute to be set
+// with SQLSetConnectAttr, for example SQL_ATTR_PACKET_SIZE.
+type ConnAttr struct {
+	Attr  api.SQLINTEGER
+	Value uintptr
+}
+
+// Connector implements driver.Connector. It can be passed
+// to sql.OpenDB to configure connections in Go code
+// instead of in the DSN.
+type Connector struct {
+	cfg Config
+	drv *Driver
+}
+
+// NewConnector returns a Connector for cfg that uses the default
+// "odbc" driver.
+func NewConnector(cfg Config) *Connector {
+	return drv.NewConnector(cfg)
+}
+
+// NewConnector returns a Connector for cfg that uses driver d.
+func (d *Driver) NewConnector(cfg Config) *Connector {
+	return &Connector{cfg: cfg, drv: d}
+}
+
+// OpenConnector implements driver.DriverContext.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	if err := d.err(); err != nil {
+		return nil, err
+	}
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return d.NewConnector(cfg), nil
+}
+
+// Config returns a copy of the connector configuration.
+func (c *Connector) Config() Config {
+	return c.cfg
+}
+
+// Driver implements driver.Connector.
+func (c *Connector) Driver() driver.Driver {
+	return c.drv
+}
+
+// durationToSeconds rounds d up to whole seconds.
+func durationToSeconds(d time.Duration) uintptr {
+	return uintptr((d + time.Second - 1) / time.Second)
+}
+
+// loginTimeout returns the login timeout for a connection
+// opened with ctx. Zero means the driver default.
+func (c *Connector) loginTimeout(ctx context.Context) (time.Duration, error) {
+	d := c.cfg.LoginTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return 0, context.DeadlineExceeded
+		}
+		if d <= 0 || left < d {
+			d = left
+		}
+	}
+	return d, nil
+}
+
+func (c *Connector) setConnAttrs(h api.SQLHDBC, loginTimeout time.Duration) error {
+	attrs := c.cfg.ConnAttrs
+	if loginTimeout > 0 {
+		attrs = append(attrs, ConnAttr{api.SQL_ATTR_LOGIN_TIMEOUT, durationToSeconds(loginTimeout)})
+	}
+	if c.cfg.ConnectionTimeout > 0 {
+		attrs = append(attrs, ConnAttr{api.SQL_ATTR_CONNECTION_TIMEOUT, durationToSeconds(c.cfg.ConnectionTimeout)})
+	}
+	for _, a := range attrs {
+		ret := api.SQLSetConnectUIntPtrAttr(h, a.Attr, a.Value, api.SQL_IS_UINTEGER)
+		if IsError(ret) {
+			return NewError("SQLSetConnectUIntPtrAttr", h)
+		}
+	}
+	return nil
+}
+
+// Connect implements driver.Connector. If ctx is done while
+// connecting, Connect calls SQLCancelHandle on the connection
+// (where the driver manager supports it) and returns ctx.Err().
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	d := c.drv
+	done, err := d.startConnect()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	loginTimeout, err := c.loginTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out api.SQLHANDLE
+	ret := api.SQLAllocHandle(api.SQL_HANDLE_DBC, api.SQLHANDLE(d.h), &out)
+	if IsError(ret) {
+		return nil, NewError("SQLAllocHandle", d.h)
+	}
+	h := api.SQLHDBC(out)
+	d.Stats.updateHandleCount(api.SQL_HANDLE_DBC, 1)
+
+	if err := c.setConnAttrs(h, loginTimeout); err != nil {
+		defer d.releaseHandle(h)
+		return nil, err
+	}
+
+	b := api.StringToUTF16(c.cfg.DSN)
+	stop := watchContext(ctx, func() {
+		api.SQLCancelHandle(api.SQL_HANDLE_DBC, api.SQLHANDLE(h))
+	})
+	ret = api.SQLDriverConnect(h, 0,
+		(*api.SQLWCHAR)(unsafe.Pointer(&b[0])), api.SQL_NTS,
+		nil, 0, nil, api.SQL_DRIVER_NOPROMPT)
+	stop()
+	if IsError(ret) {
+		defer d.releaseHandle(h)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, wrapTimeout(NewError("SQLDriverConnect", h))
+	}
+	if err := ctx.Err(); err != nil {
+		// Connected, but too late.
+		api.SQLDisconnect(h)
+		d.releaseHandle(h)
+		return nil, err
+	}
+	loc := c.cfg.Location
+	if loc == nil {
+		loc = d.Loc
+	}
+	conn := &Conn{h: h, drv: d, loc: loc, cfg: &c.cfg}
+	if err := d.addConn(conn); err != nil {
+		// Driver is being closed.
+		api.SQLDisconnect(h)
+		d.releaseHandle(h)
+		return nil, err
+	}
+	conn.detectDBMS()
+	if c.cfg.CursorType != CursorForwardOnly {
+		conn.scrollOptions, err = conn.InfoUint32(api.SQL_SCROLL_OPTIONS)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	for _, q := range c.cfg.InitStatements {
+		if err := conn.execDirect(q); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConnString is an ODBC connection string, a list of key=value
+// attributes separated by semicolons. Values that contain special
+// characters are enclosed in braces, with } escaped as }}.
+// Keys are case-insensitive. Attributes keep their order.
+type ConnString struct {
+	attrs []connStringAttr
+}
+
+type connStringAttr struct {
+	key    string
+	value  string
+	braced bool   // value was (or must be) enclosed in braces
+	raw    string // attribute as parsed, written back unchanged
+}
+
+// ParseConnString parses ODBC connection string s.
+// Attributes without value, like "key" or "key=",
+// have empty value.
+func ParseConnString(s string) (ConnString, error) {
+	var cs ConnString
+	for i := 0; i < len(s); {
+		start := i
+		// key
+		j := strings.IndexAny(s[i:], "=;")
+		if j < 0 || s[i+j] == ';' {
+			end := len(s)
+			if j >= 0 {
+				end = i + j
+			}
+			if key := strings.TrimSpace(s[i:end]); key != "" {
+				cs.attrs = append(cs.attrs, connStringAttr{key: key, raw: s[i:end]})
+			}
+			i = end + 1
+			continue
+		}
+		key := strings.TrimSpace(s[i : i+j])
+		if key == "" {
+			return ConnString{}, fmt.Errorf("odbc: connection string has empty key at offset %d", i)
+		}
+		i += j + 1
+
+		// value
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		a := connStringAttr{key: key}
+		if i < len(s) && s[i] == '{' {
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(s) {
+					return ConnString{}, fmt.Errorf("odbc: value of connection string attribute %q has no closing brace", key)
+				}
+				if s[i] == '}' {
+					if i+1 < len(s) && s[i+1] == '}' {
+						b.WriteByte('}')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			for i < len(s) && s[i] == ' ' {
+				i++
+			}
+			if i < len(s) && s[i] != ';' {
+				return ConnString{}, fmt.Errorf("odbc: unexpected %q after value of connection string attribute %q", s[i], key)
+			}
+			a.value = b.String()
+			a.braced = true
+		} else {
+			end := strings.IndexByte(s[i:], ';')
+			if end < 0 {
+				end = len(s) - i
+			}
+			a.value = strings.TrimRight(s[i:i+end], " ")
+			i += end
+		}
+		a.raw = s[start:i]
+		i++ // skip ;
+		cs.attrs = append(cs.attrs, a)
+	}
+	return cs, nil
+}
+
+func (cs *ConnString) index(key string) int {
+	for i, a := range cs.attrs {
+		if strings.EqualFold(a.key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns value of key. If key is repeated,
+// the first value is returned, as ODBC drivers do.
+func (cs *ConnString) Get(key string) (value string, ok bool) {
+	i := cs.index(key)
+	if i < 0 {
+		return "", false
+	}
+	return cs.attrs[i].value, true
+}
+
+// Values returns all values of key, in order.
+func (cs *ConnString) Values(key string) []string {
+	var vs []string
+	for _, a := range cs.attrs {
+		if strings.EqualFold(a.key, key) {
+			vs = append(vs, a.value)
+		}
+	}
+	return vs
+}
+
+// Set sets key to value. Existing key keeps its position,
+// otherwise the attribute is appended.
+func (cs *ConnString) Set(key, value string) {
+	a := connStringAttr{key: key, value: value, braced: needsBraces(value)}
+	i := cs.index(key)
+	if i < 0 {
+		cs.attrs = append(cs.attrs, a)
+		return
+	}
+	a.key = cs.attrs[i].key
+	a.braced = a.braced || cs.attrs[i].braced
+	// Copy attributes, so copies of cs are not affected, and
+	// drop repeated keys, so the new value is the one used.
+	attrs := make([]connStringAttr, 0, len(cs.attrs))
+	attrs = append(attrs, cs.attrs[:i]...)
+	attrs = append(attrs, a)
+	for _, b := range cs.attrs[i+1:] {
+		if !strings.EqualFold(b.key, key) {
+			attrs = append(attrs, b)
+		}
+	}
+	cs.attrs = attrs
+}
+
+// Del removes all occurrences of key.
+func (cs *ConnString) Del(key string) {
+	var attrs []connStringAttr
+	for _, a := range cs.attrs {
+		if !strings.EqualFold(a.key, key) {
+			attrs = append(attrs, a)
+		}
+	}
+	cs.attrs = attrs
+}
+
+// Keys returns keys in their order, as they were spelled.
+func (cs *ConnString) Keys() []string {
+	keys := make([]string, len(cs.attrs))
+	for i, a := range cs.attrs {
+		keys[i] = a.key
+	}
+	return keys
+}
+
+func needsBraces(v string) bool {
+	return strings.ContainsAny(v, ";{}") || strings.TrimSpace(v) != v
+}
+
+// String returns connection string, suitable for SQLDriverConnect.
+// Parsed attributes, that were not changed by Set, are written
+// as they were spelled.
+func (cs ConnString) String() string {
+	var b strings.Builder
+	for _, a := range cs.attrs {
+		if a.raw != "" {
+			b.WriteString(a.raw)
+			b.WriteByte(';')
+			continue
+		}
+		b.WriteString(a.key)
+		b.WriteByte('=')
+		if a.braced || needsBraces(a.value) {
+			b.WriteByte('{')
+			b.WriteString(strings.Replace(a.value, "}", "}}", -1))
+			b.WriteByte('}')
+		} else {
+			b.WriteString(a.value)
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
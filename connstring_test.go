@@ -0,0 +1,103 @@
+package odbc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConnString(t *testing.T) {
+	tests := []struct {
+		s    string
+		keys []string
+		vals []string
+		out  string
+	}{
+		{"", []string{}, []string{}, ""},
+		{
+			"Driver={ODBC Driver 17 for SQL Server};Server=host,1433;UID=sa;PWD={a;b}}c}",
+			[]string{"Driver", "Server", "UID", "PWD"},
+			[]string{"ODBC Driver 17 for SQL Server", "host,1433", "sa", "a;b}c"},
+			"Driver={ODBC Driver 17 for SQL Server};Server=host,1433;UID=sa;PWD={a;b}}c};",
+		},
+		{
+			" DSN = test ;;Database=db;",
+			[]string{"DSN", "Database"},
+			[]string{"test", "db"},
+			" DSN = test ;Database=db;",
+		},
+		{
+			"DSN=test;Empty=;",
+			[]string{"DSN", "Empty"},
+			[]string{"test", ""},
+			"DSN=test;Empty=;",
+		},
+		{
+			"DSN=test;Trusted_Connection;Empty=",
+			[]string{"DSN", "Trusted_Connection", "Empty"},
+			[]string{"test", "", ""},
+			"DSN=test;Trusted_Connection;Empty=;",
+		},
+		{
+			"Server=host  ;PWD={a b}  ",
+			[]string{"Server", "PWD"},
+			[]string{"host", "a b"},
+			"Server=host  ;PWD={a b}  ;",
+		},
+	}
+	for _, tc := range tests {
+		cs, err := ParseConnString(tc.s)
+		if err != nil {
+			t.Errorf("ParseConnString(%q) failed: %v", tc.s, err)
+			continue
+		}
+		if keys := cs.Keys(); !reflect.DeepEqual(keys, tc.keys) {
+			t.Errorf("ParseConnString(%q) keys: want %q, got %q", tc.s, tc.keys, keys)
+		}
+		for i, k := range tc.keys {
+			if v, ok := cs.Get(k); !ok || v != tc.vals[i] {
+				t.Errorf("ParseConnString(%q) value of %s: want %q, got %q", tc.s, k, tc.vals[i], v)
+			}
+		}
+		if out := cs.String(); out != tc.out {
+			t.Errorf("ParseConnString(%q).String(): want %q, got %q", tc.s, tc.out, out)
+		}
+	}
+}
+
+func TestParseConnStringErrors(t *testing.T) {
+	for _, s := range []string{
+		"=value",
+		"PWD={abc",
+		"PWD={abc}def;",
+	} {
+		if _, err := ParseConnString(s); err == nil {
+			t.Errorf("ParseConnString(%q) should fail", s)
+		}
+	}
+}
+
+func TestConnStringSetDel(t *testing.T) {
+	cs, err := ParseConnString("DSN=test;uid=a;UID=b;PWD=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cs.Get("Uid"); v != "a" {
+		t.Errorf("first value should win: want %q, got %q", "a", v)
+	}
+	copied := cs
+	cs.Set("UID", "c")
+	cs.Set("Database", "{weird}")
+	want := "DSN=test;uid=c;PWD=x;Database={{weird}}};"
+	if s := cs.String(); s != want {
+		t.Errorf("want %q, got %q", want, s)
+	}
+	if s := copied.String(); s != "DSN=test;uid=a;UID=b;PWD=x;" {
+		t.Errorf("Set modified copy: %q", s)
+	}
+	cs.Del("pwd")
+	cs.Del("missing")
+	want = "DSN=test;uid=c;Database={{weird}}};"
+	if s := cs.String(); s != want {
+		t.Errorf("want %q, got %q", want, s)
+	}
+}
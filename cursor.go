@@ -0,0 +1,165 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// CursorType selects cursor used for query results.
+type CursorType int
+
+const (
+	// CursorForwardOnly only moves forward, one row at a time.
+	// It is the default, and the fastest.
+	CursorForwardOnly CursorType = iota
+	// CursorStatic is a scrollable cursor over a snapshot
+	// of the result set.
+	CursorStatic
+	// CursorKeyset is a scrollable cursor with fixed set of rows,
+	// that sees changes to their values made by others.
+	CursorKeyset
+	// CursorDynamic is a scrollable cursor, that sees rows
+	// inserted, updated and deleted by others.
+	CursorDynamic
+)
+
+func parseCursorType(s string) (CursorType, error) {
+	switch strings.ToLower(s) {
+	case "forward":
+		return CursorForwardOnly, nil
+	case "static":
+		return CursorStatic, nil
+	case "keyset":
+		return CursorKeyset, nil
+	case "dynamic":
+		return CursorDynamic, nil
+	}
+	return 0, fmt.Errorf("unknown cursor type %q", s)
+}
+
+func (t CursorType) String() string {
+	switch t {
+	case CursorForwardOnly:
+		return "forward-only"
+	case CursorStatic:
+		return "static"
+	case CursorKeyset:
+		return "keyset"
+	case CursorDynamic:
+		return "dynamic"
+	}
+	return fmt.Sprintf("CursorType(%d)", int(t))
+}
+
+// attrs returns SQL_ATTR_CURSOR_TYPE value for t, and
+// SQL_SCROLL_OPTIONS bit, that reports its support.
+func (t CursorType) attrs() (uintptr, uint32, error) {
+	switch t {
+	case CursorStatic:
+		return api.SQL_CURSOR_STATIC, api.SQL_SO_STATIC, nil
+	case CursorKeyset:
+		return api.SQL_CURSOR_KEYSET_DRIVEN, api.SQL_SO_KEYSET_DRIVEN, nil
+	case CursorDynamic:
+		return api.SQL_CURSOR_DYNAMIC, api.SQL_SO_DYNAMIC, nil
+	}
+	return 0, 0, fmt.Errorf("odbc: invalid cursor type %v", t)
+}
+
+var errForwardOnly = errors.New("odbc: cursor is forward-only, set Config.CursorType to scroll")
+
+// setCursorType makes statement h use scrollable cursor of type t.
+// It fails, if SQL_SCROLL_OPTIONS of the driver do not include t.
+func (c *Conn) setCursorType(h api.SQLHSTMT, t CursorType) error {
+	v, bit, err := t.attrs()
+	if err != nil {
+		return err
+	}
+	if c.scrollOptions&bit == 0 {
+		return fmt.Errorf("odbc: driver does not support %v cursors (SQL_SCROLL_OPTIONS %#x)", t, c.scrollOptions)
+	}
+	ret := api.SQLSetStmtUIntPtrAttr(h, api.SQL_ATTR_CURSOR_SCROLLABLE, api.SQL_SCROLLABLE, api.SQL_IS_UINTEGER)
+	if IsError(ret) {
+		// ODBC 2 drivers only know the cursor type.
+		err := NewError("SQLSetStmtUIntPtrAttr", h)
+		if !isUnsupportedAttr(err) {
+			return err
+		}
+	}
+	ret = api.SQLSetStmtUIntPtrAttr(h, api.SQL_ATTR_CURSOR_TYPE, v, api.SQL_IS_UINTEGER)
+	if IsError(ret) {
+		return NewError("SQLSetStmtUIntPtrAttr", h)
+	}
+	return nil
+}
+
+// First moves to the first row of the result set and reads it into
+// dest, like Next does. It returns io.EOF, if the result set is
+// empty. First, Last, Prior, Absolute and Relative need scrollable
+// cursor, see Config.CursorType, and are used with sql.Conn.Raw,
+// like Rows.NextReaders. Next continues after the row they move to.
+func (r *Rows) First(dest []driver.Value) error {
+	return r.fetchScroll(api.SQL_FETCH_FIRST, 0, dest)
+}
+
+// Last moves to the last row of the result set.
+func (r *Rows) Last(dest []driver.Value) error {
+	return r.fetchScroll(api.SQL_FETCH_LAST, 0, dest)
+}
+
+// Prior moves to the previous row. It returns io.EOF
+// before the first row.
+func (r *Rows) Prior(dest []driver.Value) error {
+	return r.fetchScroll(api.SQL_FETCH_PRIOR, 0, dest)
+}
+
+// Absolute moves to row n, counted from 1. Negative n
+// counts from the end, so -1 is the last row. It returns
+// io.EOF, if there is no such row.
+func (r *Rows) Absolute(n int, dest []driver.Value) error {
+	return r.fetchScroll(api.SQL_FETCH_ABSOLUTE, n, dest)
+}
+
+// Relative moves n rows forward, or back, if n is negative.
+// It returns io.EOF, if it moves out of the result set.
+func (r *Rows) Relative(n int, dest []driver.Value) error {
+	return r.fetchScroll(api.SQL_FETCH_RELATIVE, n, dest)
+}
+
+// fetchScroll fetches row selected by orientation and offset with
+// SQLFetchScroll, and reads it into dest.
+func (r *Rows) fetchScroll(orientation api.SQLSMALLINT, offset int, dest []driver.Value) error {
+	r.lobGen++
+	if r.os.cursorType == CursorForwardOnly {
+		return errForwardOnly
+	}
+	if err := r.ctxErr(); err != nil {
+		return err
+	}
+	ret := api.SQLFetchScroll(r.os.h, orientation, api.SQLLEN(offset))
+	if ret == api.SQL_NO_DATA {
+		return io.EOF
+	}
+	if IsError(ret) {
+		if err := r.ctxErr(); err != nil {
+			return err
+		}
+		return NewError("SQLFetchScroll", r.os.h)
+	}
+	for i := range dest {
+		v, err := r.value(i)
+		if err != nil {
+			return err
+		}
+		dest[i] = v
+	}
+	return nil
+}
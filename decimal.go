@@ -0,0 +1,164 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DecimalMode selects how DECIMAL and NUMERIC columns are fetched.
+type DecimalMode int
+
+const (
+	// DecimalExact fetches decimals as text, without loss of
+	// precision. Values are returned as strings, that can be
+	// scanned into string, Decimal or float64.
+	DecimalExact DecimalMode = iota
+	// DecimalFloat fetches decimals as float64.
+	DecimalFloat
+)
+
+func parseDecimalMode(s string) (DecimalMode, error) {
+	switch strings.ToLower(s) {
+	case "exact":
+		return DecimalExact, nil
+	case "float":
+		return DecimalFloat, nil
+	}
+	return 0, fmt.Errorf("unknown decimal mode %q", s)
+}
+
+// Decimal is an exact decimal number, for example -123.45.
+// It can be scanned from DECIMAL and NUMERIC columns and
+// is sent to the database as DECIMAL. Zero value is 0.
+// Use *Decimal to scan nullable columns.
+type Decimal struct {
+	text string
+}
+
+// DecimalStringer is implemented by decimal types of other
+// packages, that should be sent as DECIMAL. DecimalString returns
+// the number in the format accepted by ParseDecimal. Types, that
+// only have String method, like shopspring/decimal.Decimal,
+// can be wrapped, or converted with ParseDecimal(d.String()).
+type DecimalStringer interface {
+	DecimalString() string
+}
+
+// ParseDecimal parses s, decimal number with optional sign and
+// fraction. Exponent notation is not accepted.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !isDecimalText(s) {
+		return Decimal{}, fmt.Errorf("odbc: invalid decimal %q", s)
+	}
+	return Decimal{text: normalizeDecimal(s)}, nil
+}
+
+// normalizeDecimal removes leading + and adds 0 before decimal
+// point, that SQL Server omits, so ".5" becomes "0.5".
+func normalizeDecimal(s string) string {
+	s = strings.TrimPrefix(s, "+")
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	if strings.HasPrefix(s, ".") {
+		s = "0" + s
+	}
+	if strings.HasSuffix(s, ".") {
+		s = s[:len(s)-1]
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// isDecimalText reports whether s is a decimal number
+// with optional sign and fraction, without exponent.
+func isDecimalText(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	digits, dot := 0, false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case '0' <= s[i] && s[i] <= '9':
+			digits++
+		case s[i] == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// String returns d in decimal notation.
+func (d Decimal) String() string {
+	if d.text == "" {
+		return "0"
+	}
+	return d.text
+}
+
+// precisionAndScale returns number of digits in d
+// and number of digits after its decimal point.
+func (d Decimal) precisionAndScale() (precision, scale int) {
+	s := d.String()
+	dot := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '.':
+			dot = true
+		case '0' <= s[i] && s[i] <= '9':
+			precision++
+			if dot {
+				scale++
+			}
+		}
+	}
+	return precision, scale
+}
+
+// Float64 returns d converted to float64.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(d.String(), 64)
+}
+
+// Scan implements sql.Scanner.
+func (d *Decimal) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return fmt.Errorf("odbc: cannot scan NULL into Decimal")
+	default:
+		return fmt.Errorf("odbc: cannot scan %T into Decimal", src)
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// Value implements driver.Valuer. This package binds Decimal
+// values as DECIMAL parameters without calling Value.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
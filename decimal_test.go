@@ -0,0 +1,75 @@
+package odbc
+
+import "testing"
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s                string
+		valid            bool
+		want             string
+		precision, scale int
+	}{
+		{"0", true, "0", 1, 0},
+		{"-123.45", true, "-123.45", 5, 2},
+		{"+.5", true, "0.5", 2, 1},
+		{"-.5000", true, "-0.5000", 5, 4},
+		{"12.", true, "12", 2, 0},
+		{"", false, "", 0, 0},
+		{"-", false, "", 0, 0},
+		{".", false, "", 0, 0},
+		{"1.2.3", false, "", 0, 0},
+		{"1e3", false, "", 0, 0},
+		{"NaN", false, "", 0, 0},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.s)
+		if !test.valid {
+			if err == nil {
+				t.Errorf("ParseDecimal(%q) should fail", test.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) failed: %v", test.s, err)
+			continue
+		}
+		if d.String() != test.want {
+			t.Errorf("ParseDecimal(%q): want %q, got %q", test.s, test.want, d.String())
+		}
+		p, s := d.precisionAndScale()
+		if p != test.precision || s != test.scale {
+			t.Errorf("precisionAndScale(%q): want %d, %d, got %d, %d", test.s, test.precision, test.scale, p, s)
+		}
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want string
+	}{
+		{"12.3400", "12.3400"},
+		{[]byte(".5"), "0.5"},
+		{int64(-7), "-7"},
+		{0.25, "0.25"},
+	}
+	for _, test := range tests {
+		var d Decimal
+		if err := d.Scan(test.src); err != nil {
+			t.Errorf("Scan(%v) failed: %v", test.src, err)
+			continue
+		}
+		if d.String() != test.want {
+			t.Errorf("Scan(%v): want %q, got %q", test.src, test.want, d.String())
+		}
+	}
+	var d Decimal
+	for _, src := range []interface{}{nil, "abc", true} {
+		if err := d.Scan(src); err == nil {
+			t.Errorf("Scan(%v) should fail", src)
+		}
+	}
+	if d.String() != "0" {
+		t.Errorf("zero Decimal should be 0, got %q", d.String())
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// DBMSInfo identifies database and ODBC driver of a connection.
+type DBMSInfo struct {
+	Name    string // SQL_DBMS_NAME, for example "Microsoft SQL Server"
+	Version string // SQL_DBMS_VER
+	Driver  string // SQL_DRIVER_NAME, for example "libmsodbcsql-17.so"
+}
+
+// Dialect describes behavior specific to a DBMS or ODBC driver.
+// Dialects are picked after connecting, by matching DBMSInfo
+// of the connection.
+type Dialect struct {
+	Name string
+	// Match reports whether dialect should be used for a connection.
+	Match func(info DBMSInfo) bool
+
+	// LongStringParams binds all string parameters as
+	// SQL_WLONGVARCHAR (SQL_LONGVARCHAR in narrow string mode).
+	// MS Access requires that for MEMO columns.
+	LongStringParams bool
+	// TrustColumnSize makes VARCHAR and VARBINARY columns bound
+	// with buffers of size reported by SQLDescribeCol, up to 1024
+	// characters. Otherwise such columns are read with SQLGetData,
+	// like long columns, because some drivers (Databricks) report
+	// wrong sizes. Only bound columns can be block fetched.
+	TrustColumnSize bool
+	// BoolAsInteger sends bool parameters as integer 0 or 1,
+	// for DBMSes without BIT type.
+	BoolAsInteger bool
+	// SnapshotIsolation allows sql.LevelSnapshot to be
+	// mapped onto SQL_TXN_SS_SNAPSHOT.
+	SnapshotIsolation bool
+	// ParamNamePrefix is prepended to names of named parameters
+	// passed to the driver with SQL_DESC_NAME.
+	ParamNamePrefix string
+
+	// Savepoint, RollbackToSavepoint and ReleaseSavepoint are fmt
+	// formats of statements that manage savepoints. Empty Savepoint
+	// means savepoints are not supported. Empty ReleaseSavepoint
+	// means savepoints cannot be released, and Tx.Release does nothing.
+	Savepoint           string
+	RollbackToSavepoint string
+	ReleaseSavepoint    string
+}
+
+// DefaultDialect is used when no registered dialect matches connection.
+var DefaultDialect = &Dialect{Name: "default"}
+
+func dbmsNameIs(names ...string) func(DBMSInfo) bool {
+	return func(info DBMSInfo) bool {
+		for _, n := range names {
+			if strings.EqualFold(info.Name, n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func dbmsNameContains(substrs ...string) func(DBMSInfo) bool {
+	return func(info DBMSInfo) bool {
+		name := strings.ToLower(info.Name)
+		for _, s := range substrs {
+			if strings.Contains(name, s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+var (
+	dialectsMu sync.RWMutex
+	dialects   = []*Dialect{
+		{
+			Name:                "mssql",
+			Match:               dbmsNameIs("Microsoft SQL Server"),
+			TrustColumnSize:     true,
+			SnapshotIsolation:   true,
+			ParamNamePrefix:     "@",
+			Savepoint:           "SAVE TRANSACTION %s",
+			RollbackToSavepoint: "ROLLBACK TRANSACTION %s",
+		},
+		{
+			Name:             "access",
+			Match:            dbmsNameIs("ACCESS"),
+			LongStringParams: true,
+		},
+		{
+			Name:                "oracle",
+			Match:               dbmsNameIs("Oracle"),
+			TrustColumnSize:     true,
+			BoolAsInteger:       true,
+			Savepoint:           "SAVEPOINT %s",
+			RollbackToSavepoint: "ROLLBACK TO SAVEPOINT %s",
+		},
+		{
+			Name:                "standard",
+			Match:               dbmsNameContains("mysql", "mariadb", "postgresql", "sqlite"),
+			TrustColumnSize:     true,
+			Savepoint:           "SAVEPOINT %s",
+			RollbackToSavepoint: "ROLLBACK TO SAVEPOINT %s",
+			ReleaseSavepoint:    "RELEASE SAVEPOINT %s",
+		},
+	}
+)
+
+// RegisterDialect adds d to the dialect registry. Dialects registered
+// later take precedence, so d can replace built-in dialects.
+func RegisterDialect(d *Dialect) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects = append(dialects, d)
+}
+
+// dialectFor returns dialect for DBMS described by info.
+func dialectFor(info DBMSInfo) *Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	for i := len(dialects) - 1; i >= 0; i-- {
+		if d := dialects[i]; d.Match != nil && d.Match(info) {
+			return d
+		}
+	}
+	return DefaultDialect
+}
+
+// detectDBMS fills c.dbms and picks c.dialect, unless
+// the dialect is set by Config. Drivers that fail
+// SQLGetInfo get the default dialect.
+func (c *Conn) detectDBMS() {
+	c.dbms.Name, _ = c.InfoString(api.SQL_DBMS_NAME)
+	c.dbms.Version, _ = c.InfoString(api.SQL_DBMS_VER)
+	c.dbms.Driver, _ = c.InfoString(api.SQL_DRIVER_NAME)
+	c.dialect = c.cfg.Dialect
+	if c.dialect == nil {
+		c.dialect = dialectFor(c.dbms)
+	}
+}
+
+// DBMS returns information about database c is connected to.
+func (c *Conn) DBMS() DBMSInfo {
+	return c.dbms
+}
+
+// Dialect returns dialect used by c.
+func (c *Conn) Dialect() *Dialect {
+	return c.dialect
+}
@@ -0,0 +1,63 @@
+package odbc
+
+import "testing"
+
+func TestDialectFor(t *testing.T) {
+	tests := []struct {
+		dbms    string
+		dialect string
+	}{
+		{"Microsoft SQL Server", "mssql"},
+		{"MySQL", "standard"},
+		{"PostgreSQL", "standard"},
+		{"Oracle", "oracle"},
+		{"ACCESS", "access"},
+		{"", "default"},
+		{"Unknown", "default"},
+	}
+	for _, tc := range tests {
+		d := dialectFor(DBMSInfo{Name: tc.dbms})
+		if d.Name != tc.dialect {
+			t.Errorf("dialectFor(%q) = %q, want %q", tc.dbms, d.Name, tc.dialect)
+		}
+	}
+}
+
+func TestDialectTrustColumnSize(t *testing.T) {
+	for _, dbms := range []string{"Microsoft SQL Server", "MySQL", "PostgreSQL", "Oracle"} {
+		if d := dialectFor(DBMSInfo{Name: dbms}); !d.TrustColumnSize {
+			t.Errorf("dialect %q for %q should trust column sizes", d.Name, dbms)
+		}
+	}
+	// Column sizes of unknown drivers are not trusted,
+	// so their VARCHAR columns are read with SQLGetData.
+	if d := dialectFor(DBMSInfo{Name: "Spark SQL"}); d.TrustColumnSize {
+		t.Errorf("dialect %q should not trust column sizes", d.Name)
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	dialectsMu.Lock()
+	saved := dialects
+	dialectsMu.Unlock()
+	defer func() {
+		dialectsMu.Lock()
+		dialects = saved
+		dialectsMu.Unlock()
+	}()
+
+	inhouse := &Dialect{
+		Name: "inhouse",
+		Match: func(info DBMSInfo) bool {
+			return info.Driver == "libinhouse.so"
+		},
+		TrustColumnSize: true,
+	}
+	RegisterDialect(inhouse)
+	if d := dialectFor(DBMSInfo{Name: "Microsoft SQL Server", Driver: "libinhouse.so"}); d != inhouse {
+		t.Errorf("registered dialect should take precedence, got %q", d.Name)
+	}
+	if d := dialectFor(DBMSInfo{Name: "Microsoft SQL Server"}); d.Name != "mssql" {
+		t.Errorf("unexpected dialect %q", d.Name)
+	}
+}
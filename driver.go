@@ -4,11 +4,25 @@
 
 // Package odbc implements database/sql driver to access data via odbc interface.
 //
+// Failed ODBC calls return *Error with the driver diagnostics. Queries
+// and connections that run out of time return *TimeoutError, which
+// wraps the *Error, so use errors.As to check for either:
+//
+//	var te *odbc.TimeoutError
+//	if errors.As(err, &te) {
+//		// retry
+//	}
+//	var oe *odbc.Error
+//	if errors.As(err, &oe) {
+//		log.Println(oe.Diag)
+//	}
 package odbc
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sigmacomputing/odbc/api"
@@ -26,9 +40,104 @@ type Driver struct {
 	h       api.SQLHENV // environment handle
 	initErr error
 	Loc     *time.Location
+	// mu protects conns and initErr.
+	mu    sync.Mutex
+	conns map[*Conn]struct{} // open connections allocated in h
+	// connecting counts Connect calls in progress, so
+	// Close does not free h, while they use it.
+	connecting sync.WaitGroup
+}
+
+// Pooling selects the driver manager connection pooling mode,
+// set as SQL_ATTR_CONNECTION_POOLING.
+type Pooling int
+
+const (
+	PoolingOff       Pooling = iota // SQL_CP_OFF
+	PoolingPerDriver                // SQL_CP_ONE_PER_DRIVER
+	PoolingPerEnv                   // SQL_CP_ONE_PER_HENV
+)
+
+// PoolMatch selects how the driver manager matches pooled
+// connections, set as SQL_ATTR_CP_MATCH.
+type PoolMatch int
+
+const (
+	StrictMatch  PoolMatch = iota // SQL_CP_STRICT_MATCH
+	RelaxedMatch                  // SQL_CP_RELAXED_MATCH
+)
+
+// DriverOptions configure ODBC environment allocated by NewDriver.
+type DriverOptions struct {
+	// Version is SQL_ATTR_ODBC_VERSION, for example api.SQL_OV_ODBC3_80.
+	// Zero means api.SQL_OV_ODBC3.
+	Version uintptr
+	Pooling Pooling
+	Match   PoolMatch
+}
+
+// DefaultDriverOptions are used by the driver registered as "odbc".
+var DefaultDriverOptions = DriverOptions{
+	Version: api.SQL_OV_ODBC3,
+	Pooling: PoolingPerEnv,
+	Match:   RelaxedMatch,
 }
 
-func initDriver() error {
+// NewDriver allocates new ODBC environment configured with opts.
+// Every driver has its own environment, so drivers with different
+// pooling settings can be used in the same process.
+func NewDriver(opts DriverOptions) (*Driver, error) {
+	d := new(Driver)
+	if err := d.init(opts); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Register creates new driver with NewDriver and registers it
+// with database/sql under name. Like sql.Register,
+// it panics if name is already registered.
+func Register(name string, opts DriverOptions) (*Driver, error) {
+	d, err := NewDriver(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			// free environment of the driver, that was not registered
+			d.Close()
+			panic(r)
+		}
+	}()
+	sql.Register(name, d)
+	return d, nil
+}
+
+func (d *Driver) init(opts DriverOptions) error {
+	var pooling uintptr
+	switch opts.Pooling {
+	case PoolingOff:
+		pooling = api.SQL_CP_OFF
+	case PoolingPerDriver:
+		pooling = api.SQL_CP_ONE_PER_DRIVER
+	case PoolingPerEnv:
+		pooling = api.SQL_CP_ONE_PER_HENV
+	default:
+		return fmt.Errorf("odbc: unknown pooling mode %d", opts.Pooling)
+	}
+	var match uintptr
+	switch opts.Match {
+	case StrictMatch:
+		match = api.SQL_CP_STRICT_MATCH
+	case RelaxedMatch:
+		match = api.SQL_CP_RELAXED_MATCH
+	default:
+		return fmt.Errorf("odbc: unknown pool match mode %d", opts.Match)
+	}
+	version := opts.Version
+	if version == 0 {
+		version = api.SQL_OV_ODBC3
+	}
 
 	//Allocate environment handle
 	var out api.SQLHANDLE
@@ -37,32 +146,30 @@ func initDriver() error {
 	if IsError(ret) {
 		return NewError("SQLAllocHandle", api.SQLHENV(in))
 	}
-	drv.h = api.SQLHENV(out)
-	err := drv.Stats.updateHandleCount(api.SQL_HANDLE_ENV, 1)
+	d.h = api.SQLHENV(out)
+	err := d.Stats.updateHandleCount(api.SQL_HANDLE_ENV, 1)
 	if err != nil {
 		return err
 	}
 
-	// will use ODBC v3
-	ret = api.SQLSetEnvUIntPtrAttr(drv.h, api.SQL_ATTR_ODBC_VERSION, api.SQL_OV_ODBC3, 0)
+	ret = api.SQLSetEnvUIntPtrAttr(d.h, api.SQL_ATTR_ODBC_VERSION, version, 0)
 	if IsError(ret) {
-		defer releaseHandle(drv.h)
-		return NewError("SQLSetEnvUIntPtrAttr", drv.h)
+		defer d.Close()
+		return NewError("SQLSetEnvUIntPtrAttr", d.h)
 	}
 
-	//TODO: find a way to make this attribute changeable at runtime
-	//Enable connection pooling
-	ret = api.SQLSetEnvUIntPtrAttr(drv.h, api.SQL_ATTR_CONNECTION_POOLING, api.SQL_CP_ONE_PER_HENV, api.SQL_IS_UINTEGER)
+	ret = api.SQLSetEnvUIntPtrAttr(d.h, api.SQL_ATTR_CONNECTION_POOLING, pooling, api.SQL_IS_UINTEGER)
 	if IsError(ret) {
-		defer releaseHandle(drv.h)
-		return NewError("SQLSetEnvUIntPtrAttr", drv.h)
+		defer d.Close()
+		return NewError("SQLSetEnvUIntPtrAttr", d.h)
 	}
 
-	//Set relaxed connection pool matching
-	ret = api.SQLSetEnvUIntPtrAttr(drv.h, api.SQL_ATTR_CP_MATCH, api.SQL_CP_RELAXED_MATCH, api.SQL_IS_UINTEGER)
-	if IsError(ret) {
-		defer releaseHandle(drv.h)
-		return NewError("SQLSetEnvUIntPtrAttr", drv.h)
+	if opts.Pooling != PoolingOff {
+		ret = api.SQLSetEnvUIntPtrAttr(d.h, api.SQL_ATTR_CP_MATCH, match, api.SQL_IS_UINTEGER)
+		if IsError(ret) {
+			defer d.Close()
+			return NewError("SQLSetEnvUIntPtrAttr", d.h)
+		}
 	}
 
 	//TODO: it would be nice if we could call "drv.SetMaxIdleConns(0)" here but from the docs it looks like
@@ -71,15 +178,90 @@ func initDriver() error {
 	return nil
 }
 
+var errDriverClosed = errors.New("odbc: driver is closed")
+
+// err returns the error, that prevents d from being used, if any.
+func (d *Driver) err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.initErr
+}
+
+// startConnect makes Close wait until the returned done is called,
+// so connection can be allocated in d environment. It fails, if d
+// is closed.
+func (d *Driver) startConnect() (done func(), err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.initErr != nil {
+		return nil, d.initErr
+	}
+	d.connecting.Add(1)
+	return d.connecting.Done, nil
+}
+
+// addConn registers c to be closed by Close. It fails, if Close
+// has already started.
+func (d *Driver) addConn(c *Conn) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.initErr != nil {
+		return d.initErr
+	}
+	if d.conns == nil {
+		d.conns = make(map[*Conn]struct{})
+	}
+	d.conns[c] = struct{}{}
+	return nil
+}
+
+func (d *Driver) removeConn(c *Conn) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.conns, c)
+}
+
+// Close closes all connections opened by d (and their statements),
+// and then frees d environment handle. d cannot be used afterwards.
+// Connections must not be used while Close runs, so close all
+// sql.DB that use d first, or make sure they are idle.
 func (d *Driver) Close() error {
-	// TODO(brainman): who will call (*Driver).Close (to dispose all opened handles)?
+	d.mu.Lock()
+	if d.initErr == nil {
+		d.initErr = errDriverClosed
+	}
+	d.mu.Unlock()
+
+	// Connect calls in progress either register their
+	// connection now, or free it, when addConn fails.
+	d.connecting.Wait()
+
+	d.mu.Lock()
+	conns := make([]*Conn, 0, len(d.conns))
+	for c := range d.conns {
+		conns = append(conns, c)
+	}
+	d.mu.Unlock()
+
+	var err error
+	for _, c := range conns {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
 	h := d.h
+	if h == api.SQLHENV(api.SQL_NULL_HENV) {
+		return err
+	}
 	d.h = api.SQLHENV(api.SQL_NULL_HENV)
-	return releaseHandle(h)
+	if e := d.releaseHandle(h); e != nil && err == nil {
+		err = e
+	}
+	return err
 }
 
 func init() {
-	err := initDriver()
+	err := drv.init(DefaultDriverOptions)
 	if err != nil {
 		drv.initErr = err
 	}
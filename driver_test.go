@@ -0,0 +1,25 @@
+package odbc
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClosedDriver(t *testing.T) {
+	d, err := NewDriver(DefaultDriverOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.OpenConnector("DSN=test"); err != errDriverClosed {
+		t.Errorf("OpenConnector: want %v, got %v", errDriverClosed, err)
+	}
+	if _, err := d.NewConnector(Config{DSN: "DSN=test"}).Connect(context.Background()); err != errDriverClosed {
+		t.Errorf("Connect: want %v, got %v", errDriverClosed, err)
+	}
+	if err := d.addConn(&Conn{}); err != errDriverClosed {
+		t.Errorf("addConn: want %v, got %v", errDriverClosed, err)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DSNPrefix starts connection string keys that configure this
+// package rather than the ODBC driver. Such keys are removed from
+// the connection string before it is passed to SQLDriverConnect.
+// Keys are case-insensitive:
+//
+//	GoODBC_Timezone           time zone name for Config.Location
+//	GoODBC_FetchSize          Config.FetchSize
+//	GoODBC_BatchSize          Config.BatchSize
+//	GoODBC_StringMode         "wide" or "narrow", see Config.StringMode
+//	GoODBC_DecimalMode        "exact" or "float", see Config.DecimalMode
+//	GoODBC_CursorType         "forward", "static", "keyset" or "dynamic", see Config.CursorType
+//	GoODBC_QueryTimeout       Config.QueryTimeout
+//	GoODBC_LoginTimeout       Config.LoginTimeout
+//	GoODBC_ConnectionTimeout  Config.ConnectionTimeout
+//	GoODBC_InitSQL            appended to Config.InitStatements, can be repeated
+//
+// Timeouts are Go durations ("30s") or whole seconds ("30").
+// Unknown keys with this prefix are rejected.
+const DSNPrefix = "GoODBC_"
+
+// StringMode selects how string parameters are sent to the driver.
+type StringMode int
+
+const (
+	// StringWide sends strings as UTF-16 (SQL_C_WCHAR).
+	StringWide StringMode = iota
+	// StringNarrow sends strings as UTF-8 (SQL_C_CHAR), for drivers
+	// with poor wide character support.
+	StringNarrow
+)
+
+func parseStringMode(s string) (StringMode, error) {
+	switch strings.ToLower(s) {
+	case "wide":
+		return StringWide, nil
+	case "narrow":
+		return StringNarrow, nil
+	}
+	return 0, fmt.Errorf("unknown string mode %q", s)
+}
+
+func parseDSNDuration(s string) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ParseDSN parses connection string dsn into Config. Keys starting
+// with DSNPrefix are applied to the Config and removed from
+// Config.DSN. Without such keys Config.DSN is dsn unchanged.
+// Location also defaults to SSP_timezone, which is passed to
+// the driver unchanged.
+func ParseDSN(dsn string) (Config, error) {
+	cs, err := ParseConnString(dsn)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg := Config{DSN: dsn}
+	cfg.Location, err = timezoneFromConnString(&cs)
+	if err != nil {
+		return Config{}, err
+	}
+	reserved := false
+	for _, k := range cs.Keys() {
+		if len(k) < len(DSNPrefix) || !strings.EqualFold(k[:len(DSNPrefix)], DSNPrefix) {
+			continue
+		}
+		if _, ok := cs.Get(k); !ok {
+			// repeated key, already handled
+			continue
+		}
+		if err := cfg.setDSNOption(k[len(DSNPrefix):], &cs); err != nil {
+			return Config{}, fmt.Errorf("odbc: invalid %s: %v", k, err)
+		}
+		cs.Del(k)
+		reserved = true
+	}
+	if reserved {
+		cfg.DSN = cs.String()
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) setDSNOption(name string, cs *ConnString) error {
+	var err error
+	switch strings.ToLower(name) {
+	case "timezone":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.Location, err = time.LoadLocation(strings.TrimSpace(v))
+	case "fetchsize":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.FetchSize, err = strconv.Atoi(v)
+		if err == nil && cfg.FetchSize < 0 {
+			err = fmt.Errorf("negative value %d", cfg.FetchSize)
+		}
+	case "batchsize":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.BatchSize, err = strconv.Atoi(v)
+		if err == nil && cfg.BatchSize < 0 {
+			err = fmt.Errorf("negative value %d", cfg.BatchSize)
+		}
+	case "stringmode":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.StringMode, err = parseStringMode(v)
+	case "decimalmode":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.DecimalMode, err = parseDecimalMode(v)
+	case "cursortype":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.CursorType, err = parseCursorType(v)
+	case "querytimeout":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.QueryTimeout, err = parseDSNDuration(v)
+	case "logintimeout":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.LoginTimeout, err = parseDSNDuration(v)
+	case "connectiontimeout":
+		v, _ := cs.Get(DSNPrefix + name)
+		cfg.ConnectionTimeout, err = parseDSNDuration(v)
+	case "initsql":
+		cfg.InitStatements = append(cfg.InitStatements, cs.Values(DSNPrefix+name)...)
+	default:
+		err = fmt.Errorf("unknown %s option", DSNPrefix)
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package odbc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDSN(t *testing.T) {
+	cfg, err := ParseDSN("Driver={Some Driver};GoODBC_Timezone=America/New_York;" +
+		"goodbc_fetchsize=500;GoODBC_StringMode=narrow;GoODBC_DecimalMode=float;GoODBC_QueryTimeout=30;" +
+		"GoODBC_CursorType=Keyset;" +
+		"GoODBC_LoginTimeout=1m;GoODBC_ConnectionTimeout=90s;" +
+		"GoODBC_InitSQL={set ansi_nulls on};GoODBC_InitSQL=set nocount on;UID=sa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Driver={Some Driver};UID=sa;"; cfg.DSN != want {
+		t.Errorf("DSN: want %q, got %q", want, cfg.DSN)
+	}
+	if cfg.Location.String() != "America/New_York" {
+		t.Errorf("unexpected location %v", cfg.Location)
+	}
+	if cfg.FetchSize != 500 {
+		t.Errorf("unexpected fetch size %d", cfg.FetchSize)
+	}
+	if cfg.StringMode != StringNarrow {
+		t.Errorf("unexpected string mode %d", cfg.StringMode)
+	}
+	if cfg.DecimalMode != DecimalFloat {
+		t.Errorf("unexpected decimal mode %d", cfg.DecimalMode)
+	}
+	if cfg.CursorType != CursorKeyset {
+		t.Errorf("unexpected cursor type %v", cfg.CursorType)
+	}
+	if cfg.QueryTimeout != 30*time.Second || cfg.LoginTimeout != time.Minute || cfg.ConnectionTimeout != 90*time.Second {
+		t.Errorf("unexpected timeouts %v, %v, %v", cfg.QueryTimeout, cfg.LoginTimeout, cfg.ConnectionTimeout)
+	}
+	if want := []string{"set ansi_nulls on", "set nocount on"}; !reflect.DeepEqual(cfg.InitStatements, want) {
+		t.Errorf("InitStatements: want %q, got %q", want, cfg.InitStatements)
+	}
+}
+
+func TestParseDSNKeepsSSPTimezone(t *testing.T) {
+	cfg, err := ParseDSN("Driver=databricks;SSP_timezone=Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Driver=databricks;SSP_timezone=Europe/Paris"; cfg.DSN != want {
+		t.Errorf("DSN: want %q, got %q", want, cfg.DSN)
+	}
+	if cfg.Location.String() != "Europe/Paris" {
+		t.Errorf("unexpected location %v", cfg.Location)
+	}
+}
+
+func TestParseDSNUnchanged(t *testing.T) {
+	for _, dsn := range []string{
+		"DSN=test",
+		" DSN = test ;UID=sa  ;",
+		"Driver={Some Driver};Trusted_Connection;Database=",
+	} {
+		cfg, err := ParseDSN(dsn)
+		if err != nil {
+			t.Errorf("ParseDSN(%q) failed: %v", dsn, err)
+			continue
+		}
+		if cfg.DSN != dsn {
+			t.Errorf("DSN: want %q, got %q", dsn, cfg.DSN)
+		}
+	}
+	cfg, err := ParseDSN("DSN=test ;Trusted_Connection;GoODBC_FetchSize=10;UID=sa  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "DSN=test ;Trusted_Connection;UID=sa  ;"; cfg.DSN != want {
+		t.Errorf("DSN: want %q, got %q", want, cfg.DSN)
+	}
+}
+
+func TestParseDSNErrors(t *testing.T) {
+	for _, dsn := range []string{
+		"DSN=test;GoODBC_Unknown=1",
+		"DSN=test;GoODBC_FetchSize=many",
+		"DSN=test;GoODBC_FetchSize=-1",
+		"DSN=test;GoODBC_StringMode=ascii",
+		"DSN=test;GoODBC_DecimalMode=double",
+		"DSN=test;GoODBC_CursorType=scroll",
+		"DSN=test;GoODBC_QueryTimeout=soon",
+		"DSN=test;GoODBC_Timezone=GMT-8",
+	} {
+		if _, err := ParseDSN(dsn); err == nil {
+			t.Errorf("ParseDSN(%q) should fail", dsn)
+		}
+	}
+}
@@ -72,3 +72,55 @@ func NewError(apiName string, handle interface{}) error {
 	}
 	return err
 }
+
+// hasState reports whether any of e diagnostic records has SQLSTATE state.
+func (e *Error) hasState(state string) bool {
+	for _, r := range e.Diag {
+		if r.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Error) isTimeout() bool {
+	return e.hasState("HYT00") || e.hasState("HYT01")
+}
+
+// isUnsupportedAttr reports whether err means, that the driver
+// does not support statement attribute or its value.
+func isUnsupportedAttr(err error) bool {
+	e, ok := err.(*Error)
+	return ok && (e.hasState("HYC00") || e.hasState("HY092") || e.hasState("HY024"))
+}
+
+// TimeoutError is returned by Stmt Exec and Query methods, and by
+// Connector.Connect, instead of Error when ODBC call failed because
+// a timeout expired (SQLSTATE HYT00 or HYT01), so callers can tell
+// timed out queries from failed ones. It wraps the Error, use
+// errors.As to get either of them.
+type TimeoutError struct {
+	Err *Error
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+// Timeout reports whether the error is a timeout. It is always true.
+func (e *TimeoutError) Timeout() bool {
+	return true
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// wrapTimeout returns err wrapped in TimeoutError,
+// if err is Error reporting expired timeout.
+func wrapTimeout(err error) error {
+	if e, ok := err.(*Error); ok && e.isTimeout() {
+		return &TimeoutError{Err: e}
+	}
+	return err
+}
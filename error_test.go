@@ -0,0 +1,32 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrapTimeout(t *testing.T) {
+	timeout := &Error{APIName: "SQLExecute", Diag: []DiagRecord{{State: "HYT00"}}}
+	err := wrapTimeout(timeout)
+	var te *TimeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("want *TimeoutError, got %T", err)
+	}
+	var oe *Error
+	if !errors.As(err, &oe) || oe != timeout {
+		t.Errorf("errors.As(*Error): want %v, got %v", timeout, oe)
+	}
+
+	failed := &Error{APIName: "SQLExecute", Diag: []DiagRecord{{State: "42000"}}}
+	if err := wrapTimeout(failed); err != failed {
+		t.Errorf("want %v unchanged, got %T", failed, err)
+	}
+	other := errors.New("other")
+	if err := wrapTimeout(other); err != other {
+		t.Errorf("want %v unchanged, got %T", other, err)
+	}
+}
@@ -5,6 +5,7 @@
 package odbc
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sigmacomputing/odbc/api"
@@ -25,13 +26,17 @@ func ToHandleAndType(handle interface{}) (h api.SQLHANDLE, ht api.SQLSMALLINT, e
 	case api.SQLHSTMT:
 		ht = api.SQL_HANDLE_STMT
 		h = api.SQLHANDLE(v)
+	case api.SQLHDESC:
+		ht = api.SQL_HANDLE_DESC
+		h = api.SQLHANDLE(v)
 	default:
 		err = fmt.Errorf("unexpected handle type %T", v)
 	}
 	return h, ht, err
 }
 
-func releaseHandle(handle interface{}) error {
+// releaseHandle frees handle allocated in environment of d.
+func (d *Driver) releaseHandle(handle interface{}) error {
 	h, ht, err := ToHandleAndType(handle)
 	if err != nil {
 		return err
@@ -43,5 +48,30 @@ func releaseHandle(handle interface{}) error {
 	if IsError(ret) {
 		return NewError("SQLFreeHandle", handle)
 	}
-	return drv.Stats.updateHandleCount(ht, -1)
+	return d.Stats.updateHandleCount(ht, -1)
+}
+
+// watchContext starts watching ctx and calls cancel if ctx is
+// done before the returned stop function is called. stop waits
+// for the watcher to exit, so cancel is never called after stop
+// returns.
+func watchContext(ctx context.Context, cancel func()) (stop func()) {
+	done := ctx.Done()
+	if done == nil {
+		return func() {}
+	}
+	stopc := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-done:
+			cancel()
+		case <-stopc:
+		}
+	}()
+	return func() {
+		close(stopc)
+		<-exited
+	}
 }
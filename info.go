@@ -0,0 +1,124 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"strings"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// Info holds commonly used SQLGetInfo values. Values the driver
+// does not support are left empty. Bitmasks are described by
+// api constants, like api.SQL_TXN_SERIALIZABLE or api.SQL_GD_ANY_ORDER.
+type Info struct {
+	DBMSName          string // SQL_DBMS_NAME
+	DBMSVersion       string // SQL_DBMS_VER
+	DriverName        string // SQL_DRIVER_NAME
+	DriverVersion     string // SQL_DRIVER_VER
+	DriverODBCVersion string // SQL_DRIVER_ODBC_VER
+
+	IdentifierQuoteChar  string   // SQL_IDENTIFIER_QUOTE_CHAR, " " if quoting is not supported
+	CatalogNameSeparator string   // SQL_CATALOG_NAME_SEPARATOR
+	SearchPatternEscape  string   // SQL_SEARCH_PATTERN_ESCAPE
+	CatalogTerm          string   // SQL_CATALOG_TERM
+	SchemaTerm           string   // SQL_SCHEMA_TERM
+	TableTerm            string   // SQL_TABLE_TERM
+	ProcedureTerm        string   // SQL_PROCEDURE_TERM
+	Keywords             []string // SQL_KEYWORDS, DBMS specific keywords
+
+	MaxCatalogNameLen int // SQL_MAX_CATALOG_NAME_LEN, 0 if there is no limit
+	MaxSchemaNameLen  int // SQL_MAX_SCHEMA_NAME_LEN
+	MaxTableNameLen   int // SQL_MAX_TABLE_NAME_LEN
+	MaxColumnNameLen  int // SQL_MAX_COLUMN_NAME_LEN
+
+	TxnIsolationOptions      uint32 // SQL_TXN_ISOLATION_OPTION bitmask
+	DefaultTxnIsolation      uint32 // SQL_DEFAULT_TXN_ISOLATION
+	SQLConformance           uint32 // SQL_SQL_CONFORMANCE, for example api.SQL_SC_SQL92_ENTRY
+	ODBCInterfaceConformance uint32 // SQL_ODBC_INTERFACE_CONFORMANCE, for example api.SQL_OIC_CORE
+	GetDataExtensions        uint32 // SQL_GETDATA_EXTENSIONS bitmask
+	ScrollOptions            uint32 // SQL_SCROLL_OPTIONS bitmask
+}
+
+// infoUnsupported reports whether err means that
+// the driver does not know the information type.
+func infoUnsupported(err error) bool {
+	e, ok := err.(*Error)
+	return ok && (e.hasState("HY096") || e.hasState("HYC00"))
+}
+
+// Info returns information about c driver and data source.
+// It is meant for use with sql.Conn.Raw.
+func (c *Conn) Info() (*Info, error) {
+	var info Info
+	strs := []struct {
+		t api.SQLUSMALLINT
+		v *string
+	}{
+		{api.SQL_DBMS_NAME, &info.DBMSName},
+		{api.SQL_DBMS_VER, &info.DBMSVersion},
+		{api.SQL_DRIVER_NAME, &info.DriverName},
+		{api.SQL_DRIVER_VER, &info.DriverVersion},
+		{api.SQL_DRIVER_ODBC_VER, &info.DriverODBCVersion},
+		{api.SQL_IDENTIFIER_QUOTE_CHAR, &info.IdentifierQuoteChar},
+		{api.SQL_CATALOG_NAME_SEPARATOR, &info.CatalogNameSeparator},
+		{api.SQL_SEARCH_PATTERN_ESCAPE, &info.SearchPatternEscape},
+		{api.SQL_CATALOG_TERM, &info.CatalogTerm},
+		{api.SQL_SCHEMA_TERM, &info.SchemaTerm},
+		{api.SQL_TABLE_TERM, &info.TableTerm},
+		{api.SQL_PROCEDURE_TERM, &info.ProcedureTerm},
+	}
+	for _, s := range strs {
+		v, err := c.InfoString(s.t)
+		if err != nil && !infoUnsupported(err) {
+			return nil, err
+		}
+		*s.v = v
+	}
+	keywords, err := c.InfoString(api.SQL_KEYWORDS)
+	if err != nil && !infoUnsupported(err) {
+		return nil, err
+	}
+	if keywords != "" {
+		info.Keywords = strings.Split(keywords, ",")
+	}
+
+	smallints := []struct {
+		t api.SQLUSMALLINT
+		v *int
+	}{
+		{api.SQL_MAX_CATALOG_NAME_LEN, &info.MaxCatalogNameLen},
+		{api.SQL_MAX_SCHEMA_NAME_LEN, &info.MaxSchemaNameLen},
+		{api.SQL_MAX_TABLE_NAME_LEN, &info.MaxTableNameLen},
+		{api.SQL_MAX_COLUMN_NAME_LEN, &info.MaxColumnNameLen},
+	}
+	for _, s := range smallints {
+		v, err := c.InfoUint16(s.t)
+		if err != nil && !infoUnsupported(err) {
+			return nil, err
+		}
+		*s.v = int(v)
+	}
+
+	ints := []struct {
+		t api.SQLUSMALLINT
+		v *uint32
+	}{
+		{api.SQL_TXN_ISOLATION_OPTION, &info.TxnIsolationOptions},
+		{api.SQL_DEFAULT_TXN_ISOLATION, &info.DefaultTxnIsolation},
+		{api.SQL_SQL_CONFORMANCE, &info.SQLConformance},
+		{api.SQL_ODBC_INTERFACE_CONFORMANCE, &info.ODBCInterfaceConformance},
+		{api.SQL_GETDATA_EXTENSIONS, &info.GetDataExtensions},
+		{api.SQL_SCROLL_OPTIONS, &info.ScrollOptions},
+	}
+	for _, s := range ints {
+		v, err := c.InfoUint32(s.t)
+		if err != nil && !infoUnsupported(err) {
+			return nil, err
+		}
+		*s.v = v
+	}
+	return &info, nil
+}
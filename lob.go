@@ -0,0 +1,202 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// lobChunkSize is size of buffer used to fetch text LOB data.
+const lobChunkSize = 32 * 1024
+
+var errLOBReaderInvalid = errors.New("odbc: LOB reader is used after its row or column has been left")
+
+// LOBReader streams value of a long column, like varbinary(max) or
+// nvarchar(max), directly from the driver with SQLGetData, without
+// loading whole value into memory. Text is returned UTF-8 encoded.
+// LOBReader is returned by Rows.NextReaders. It stays valid until
+// next call to Next or NextReaders, or until any later column of
+// the same row is read. Long columns have to be read in order.
+type LOBReader struct {
+	rows  *Rows
+	col   int
+	gen   int
+	ctype api.SQLSMALLINT
+	buf   []byte // raw text chunk
+	text  []byte // converted text not yet returned
+	hi    uint16 // high surrogate left from previous chunk
+	null  bool
+	eof   bool
+}
+
+// Read implements io.Reader. NULL value reads as empty.
+func (l *LOBReader) Read(p []byte) (int, error) {
+	if l.rows.lobGen != l.gen || l.rows.lobCol > l.col {
+		return 0, errLOBReaderInvalid
+	}
+	if len(l.text) == 0 && l.eof {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	l.rows.lobCol = l.col
+	if l.ctype == api.SQL_C_BINARY {
+		n, err := l.getData(p)
+		if err == nil && n == 0 && l.eof {
+			err = io.EOF
+		}
+		return n, err
+	}
+	for len(l.text) == 0 && !l.eof {
+		if l.buf == nil {
+			l.buf = make([]byte, lobChunkSize)
+		}
+		n, err := l.getData(l.buf)
+		if err != nil {
+			return 0, err
+		}
+		if l.ctype == api.SQL_C_WCHAR {
+			var s []uint16
+			if n > 0 {
+				s = (*[1 << 28]uint16)(unsafe.Pointer(&l.buf[0]))[: n/2 : n/2]
+			}
+			l.text, l.hi = decodeUTF16Chunk(l.hi, s, l.eof)
+		} else {
+			l.text = l.buf[:n]
+		}
+	}
+	if len(l.text) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, l.text)
+	l.text = l.text[n:]
+	return n, nil
+}
+
+// Null reports whether the value is NULL. It is only
+// known after Read has been called at least once.
+func (l *LOBReader) Null() bool {
+	return l.null
+}
+
+// getData fetches next chunk of data into b and returns number
+// of data bytes received, not counting null-termination character.
+func (l *LOBReader) getData(b []byte) (int, error) {
+	h := l.rows.os.h
+	var n BufferLen
+	ret := n.GetData(h, l.col, l.ctype, b)
+	switch ret {
+	case api.SQL_SUCCESS:
+		l.eof = true
+		if n.IsNull() {
+			l.null = true
+			return 0, nil
+		}
+		if int(n) > len(b) {
+			return 0, fmt.Errorf("too much data returned: %d bytes returned, but buffer size is %d", n, len(b))
+		}
+		return int(n), nil
+	case api.SQL_SUCCESS_WITH_INFO:
+		err := NewError("SQLGetData", h)
+		if e, ok := err.(*Error); !ok || len(e.Diag) > 0 && e.Diag[0].State != "01004" {
+			return 0, err
+		}
+		i := len(b)
+		switch l.ctype {
+		case api.SQL_C_WCHAR:
+			i -= 2 // remove wchar (2 bytes) null-termination character
+		case api.SQL_C_CHAR:
+			i-- // remove null-termination character
+		}
+		return i, nil
+	case api.SQL_NO_DATA:
+		l.eof = true
+		return 0, nil
+	}
+	if err := l.rows.ctxErr(); err != nil {
+		return 0, err
+	}
+	return 0, NewError("SQLGetData", h)
+}
+
+// decodeUTF16Chunk converts chunk s of UTF-16 text, prefixed with high
+// surrogate hi if it is not zero, to UTF-8. Unless s is the last chunk,
+// its trailing high surrogate is returned to be prepended to next chunk.
+func decodeUTF16Chunk(hi uint16, s []uint16, last bool) ([]byte, uint16) {
+	if hi != 0 {
+		s = append([]uint16{hi}, s...)
+	}
+	hi = 0
+	if !last && len(s) > 0 && surr1 <= s[len(s)-1] && s[len(s)-1] < surr2 {
+		hi = s[len(s)-1]
+		s = s[:len(s)-1]
+	}
+	return utf16toutf8(s), hi
+}
+
+// isLOB reports whether c values are returned as *LOBReader by NextReaders.
+func (c *NonBindableColumn) isLOB() bool {
+	return c.SQLType != api.SQL_NUMERIC && c.SQLType != api.SQL_DECIMAL
+}
+
+// NextReaders is like Next, but values of long character and binary
+// columns, that Next reads into memory whole, are returned as
+// *LOBReader instead. Other columns are returned as by Next.
+// Reading a column, that follows LOB column, invalidates reader of
+// that LOB, so place long columns last in the select list. Use it
+// with sql.Conn.Raw:
+//
+//	err := conn.Raw(func(dc interface{}) error {
+//		st, err := dc.(*odbc.Conn).PrepareContext(ctx, "select id, data from t")
+//		...
+//		rows, err := st.(*odbc.Stmt).QueryContext(ctx, nil)
+//		...
+//		dest := make([]driver.Value, 2)
+//		for rows.(*odbc.Rows).NextReaders(dest) == nil {
+//			io.Copy(w, dest[1].(*odbc.LOBReader))
+//		}
+//		...
+//	})
+func (r *Rows) NextReaders(dest []driver.Value) error {
+	if r.os.rowStatus != nil {
+		// all columns are bound, there are no LOBs
+		return r.Next(dest)
+	}
+	r.lobGen++
+	r.lobCol = -1
+	if err := r.ctxErr(); err != nil {
+		return err
+	}
+	if err := r.fetch(); err != nil {
+		return err
+	}
+	for i := range dest {
+		switch c := r.os.Cols[i].(type) {
+		case *NonBindableColumn:
+			if c.isLOB() {
+				dest[i] = &LOBReader{rows: r, col: i, gen: r.lobGen, ctype: c.CType}
+				continue
+			}
+			r.lobCol = i
+		case *BindableColumn:
+			if !c.IsBound {
+				r.lobCol = i
+			}
+		}
+		v, err := r.value(i)
+		if err != nil {
+			return err
+		}
+		dest[i] = v
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package odbc
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+func TestDecodeUTF16Chunk(t *testing.T) {
+	s := utf16.Encode([]rune("a\U0001F600b"))
+	// split inside surrogate pair
+	text, hi := decodeUTF16Chunk(0, s[:2], false)
+	if string(text) != "a" || hi != s[1] {
+		t.Fatalf("first chunk: got %q, %#x", text, hi)
+	}
+	text, hi = decodeUTF16Chunk(hi, s[2:], true)
+	if string(text) != "\U0001F600b" || hi != 0 {
+		t.Fatalf("second chunk: got %q, %#x", text, hi)
+	}
+	// lone high surrogate at the end of the last chunk
+	text, hi = decodeUTF16Chunk(0, s[:2], true)
+	if string(text) != "a�" || hi != 0 {
+		t.Fatalf("last chunk: got %q, %#x", text, hi)
+	}
+	text, hi = decodeUTF16Chunk(s[1], nil, true)
+	if string(text) != "�" || hi != 0 {
+		t.Fatalf("empty last chunk: got %q, %#x", text, hi)
+	}
+}
@@ -6,13 +6,16 @@ package odbc
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -20,6 +23,7 @@ import (
 	"sync/atomic"
 	"testing"
 	"time"
+	"unsafe"
 
 	"github.com/sigmacomputing/odbc/api"
 )
@@ -103,11 +107,11 @@ func (params connParams) makeODBCConnectionString() string {
 		params["server"] += "," + port
 		delete(params, "port")
 	}
-	var c string
+	var c ConnString
 	for n, v := range params {
-		c += n + "=" + v + ";"
+		c.Set(n, v)
 	}
-	return c
+	return c.String()
 }
 
 func mssqlConnectWithParams(params connParams) (db *sql.DB, stmtCount int, err error) {
@@ -586,13 +590,16 @@ var typeTests = []typeTest{
 	{"select cast(9223372036854775807 as bigint)", match(int64(9223372036854775807))},
 
 	// decimal, float, real
-	{"select cast(123 as decimal(5, 0))", match(float64(123))},
-	{"select cast(-123 as decimal(5, 0))", match(float64(-123))},
-	{"select cast(123.5 as decimal(5, 0))", match(float64(124))},
+	{"select cast(123 as decimal(5, 0))", match("123")},
+	{"select cast(-123 as decimal(5, 0))", match("-123")},
+	{"select cast(123.5 as decimal(5, 0))", match("124")},
 	{"select cast(NULL as decimal(5, 0))", match(nil)},
-	{"select cast(123.45 as decimal(5, 2))", match(123.45)},
-	{"select cast(-123.45 as decimal(5, 2))", match(-123.45)},
-	{"select cast(123.456 as decimal(5, 2))", match(123.46)},
+	{"select cast(123.45 as decimal(5, 2))", match("123.45")},
+	{"select cast(-123.45 as decimal(5, 2))", match("-123.45")},
+	{"select cast(123.456 as decimal(5, 2))", match("123.46")},
+	{"select cast(0.5 as decimal(5, 2))", match("0.50")},
+	{"select cast(-0.5 as decimal(5, 2))", match("-0.50")},
+	{"select cast(12345678901234567890.123456789 as decimal(38, 9))", match("12345678901234567890.123456789")},
 	{"select cast(0.123456789 as float)", match(0.123456789)},
 	{"select cast(NULL as float)", match(nil)},
 	{"select cast(3.6666667461395264 as real)", match(3.6666667461395264)},
@@ -600,13 +607,13 @@ var typeTests = []typeTest{
 	{"select cast(1.2333333504e+10 as real)", match(1.2333333504e+10)},
 
 	// money
-	{"select cast(12 as money)", match(float64(12))},
-	{"select cast(-12 as money)", match(float64(-12))},
-	{"select cast(0.01 as money)", match(0.01)},
-	{"select cast(0.0123 as money)", match(0.0123)},
+	{"select cast(12 as money)", match("12.0000")},
+	{"select cast(-12 as money)", match("-12.0000")},
+	{"select cast(0.01 as money)", match("0.0100")},
+	{"select cast(0.0123 as money)", match("0.0123")},
 	{"select cast(NULL as money)", match(nil)},
-	{"select cast(1 as smallmoney)", match(float64(1))},
-	{"select cast(0.0123 as smallmoney)", match(0.0123)},
+	{"select cast(1 as smallmoney)", match("1.0000")},
+	{"select cast(0.0123 as smallmoney)", match("0.0123")},
 	{"select cast(NULL as smallmoney)", match(nil)},
 
 	// strings
@@ -1917,3 +1924,1175 @@ IF @@ROWCOUNT = 0
 		t.Fatal(err)
 	}
 }
+
+func TestMSSQLConnector(t *testing.T) {
+	sc := GetDriver().Stats.StmtCount
+	db := sql.OpenDB(NewConnector(Config{
+		DSN:            newConnParams().makeODBCConnectionString(),
+		LoginTimeout:   10 * time.Second,
+		InitStatements: []string{"set language british"},
+	}))
+	defer closeDB(t, db, sc, sc)
+
+	var lang string
+	if err := db.QueryRow("select @@language").Scan(&lang); err != nil {
+		t.Fatal(err)
+	}
+	if lang != "British" {
+		t.Fatalf("init statement was not executed: language is %q", lang)
+	}
+}
+
+func TestMSSQLContextCancel(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = db.ExecContext(ctx, "waitfor delay '00:00:10'")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("unexpected error: want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("statement was not cancelled: it ran for %v", d)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	rows, err := db.QueryContext(ctx, "select 1 union all select 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal(rows.Err())
+	}
+	cancel()
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != context.Canceled {
+		t.Fatalf("unexpected error: want %v, got %v", context.Canceled, err)
+	}
+
+	// connection must still be usable after cancellation
+	var n int
+	if err := db.QueryRow("select 1").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMSSQLQueryTimeout(t *testing.T) {
+	sc := GetDriver().Stats.StmtCount
+	db := sql.OpenDB(NewConnector(Config{
+		DSN:          newConnParams().makeODBCConnectionString(),
+		QueryTimeout: time.Second,
+	}))
+	defer closeDB(t, db, sc, sc)
+
+	_, err := db.Exec("waitfor delay '00:00:10'")
+	if _, ok := err.(*TimeoutError); !ok {
+		t.Fatalf("unexpected error: want *TimeoutError, got %T: %v", err, err)
+	}
+	if _, err := db.Exec("waitfor delay '00:00:00.1'"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMSSQLConnectTimeout(t *testing.T) {
+	// server that accepts connections, but never replies
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close()
+		}
+	}()
+
+	params := newConnParams()
+	if err := params.updateConnAddress(ln.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	c := NewConnector(Config{DSN: params.makeODBCConnectionString()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	start := time.Now()
+	dc, err := c.Connect(ctx)
+	if err == nil {
+		dc.Close()
+		t.Fatal("Connect to unresponsive server succeeded")
+	}
+	if err != context.DeadlineExceeded {
+		if _, ok := err.(*TimeoutError); !ok {
+			t.Fatalf("unexpected error: want timeout, got %T: %v", err, err)
+		}
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Fatalf("Connect took too long: %v", d)
+	}
+}
+
+func TestMSSQLTxIsolation(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+	db.SetMaxOpenConns(1)
+
+	const levelQuery = "select transaction_isolation_level from sys.dm_exec_sessions where session_id = @@spid"
+	var was, is int
+	if err := db.QueryRow(levelQuery).Scan(&was); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.QueryRow(levelQuery).Scan(&is); err != nil {
+		t.Fatal(err)
+	}
+	if is != 4 {
+		t.Errorf("unexpected isolation level inside transaction: want 4 (serializable), got %d", is)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.QueryRow(levelQuery).Scan(&is); err != nil {
+		t.Fatal(err)
+	}
+	if is != was {
+		t.Errorf("isolation level was not restored: want %d, got %d", was, is)
+	}
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelLinearizable})
+	if err == nil {
+		t.Fatal("BeginTx with unsupported isolation level should fail")
+	}
+}
+
+func TestMSSQLSavepoints(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	exec(t, db, "create table dbo.temp (id int)")
+	defer exec(t, db, "drop table dbo.temp")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	savepoint := func(f func(tx *Tx) error) {
+		t.Helper()
+		err := conn.Raw(func(dc interface{}) error {
+			return f(dc.(*Conn).Tx())
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tx.Exec("insert into dbo.temp (id) values (1)"); err != nil {
+		t.Fatal(err)
+	}
+	savepoint(func(tx *Tx) error { return tx.Savepoint("sp1") })
+	if _, err := tx.Exec("insert into dbo.temp (id) values (2)"); err != nil {
+		t.Fatal(err)
+	}
+	savepoint(func(tx *Tx) error { return tx.RollbackTo("sp1") })
+	savepoint(func(tx *Tx) error { return tx.Release("sp1") })
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	if err := db.QueryRow("select count(*) from dbo.temp").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("unexpected row count after rollback to savepoint: want 1, got %d", n)
+	}
+}
+
+func TestMSSQLPing(t *testing.T) {
+	sc := GetDriver().Stats.StmtCount
+	db := sql.OpenDB(NewConnector(Config{
+		DSN:             newConnParams().makeODBCConnectionString(),
+		ValidationQuery: "select 1",
+	}))
+	defer closeDB(t, db, sc, sc)
+
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Kill our own session from another connection and
+	// check that the pool notices the dead connection.
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spid int
+	if err := conn.QueryRowContext(ctx, "select @@spid").Scan(&spid); err != nil {
+		t.Fatal(err)
+	}
+	db2, sc2, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db2, sc2, sc2)
+	exec(t, db2, fmt.Sprintf("kill %d", spid))
+
+	if err := conn.PingContext(ctx); err != driver.ErrBadConn {
+		t.Fatalf("unexpected ping error: want %v, got %v", driver.ErrBadConn, err)
+	}
+	conn.Close()
+	if err := db.Ping(); err != nil {
+		t.Fatalf("pool returned dead connection: %v", err)
+	}
+}
+
+func TestMSSQLNewDriver(t *testing.T) {
+	d, err := Register("odbc-nopool", DriverOptions{
+		Version: api.SQL_OV_ODBC3_80,
+		Pooling: PoolingOff,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("odbc-nopool", newConnParams().makeODBCConnectionString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db.QueryRow("select 1").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d.Stats.ConnCount != 0 || d.Stats.StmtCount != 0 {
+		t.Fatalf("driver has %d connections and %d statements open", d.Stats.ConnCount, d.Stats.StmtCount)
+	}
+	if GetDriver().Stats.ConnCount != 0 {
+		t.Fatal("connections of new driver counted in default driver")
+	}
+}
+
+func TestMSSQLDriverClose(t *testing.T) {
+	d, err := NewDriver(DefaultDriverOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sql.OpenDB(d.NewConnector(Config{DSN: newConnParams().makeODBCConnectionString()}))
+	defer db.Close()
+
+	// Leave statement and rows open.
+	st, err := db.Prepare("select 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	rows, err := st.Query()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	if d.Stats.ConnCount == 0 || d.Stats.StmtCount == 0 {
+		t.Fatalf("unexpected handle counts: %d connections, %d statements", d.Stats.ConnCount, d.Stats.StmtCount)
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if d.Stats.EnvCount != 0 || d.Stats.ConnCount != 0 || d.Stats.StmtCount != 0 {
+		t.Fatalf("handles left after Close: %d environments, %d connections, %d statements",
+			d.Stats.EnvCount, d.Stats.ConnCount, d.Stats.StmtCount)
+	}
+	if _, err := db.Exec("select 1"); err == nil {
+		t.Fatal("closed driver should fail to connect")
+	}
+}
+
+func TestMSSQLInfo(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var info *Info
+	var quote string
+	err = conn.Raw(func(dc interface{}) error {
+		c := dc.(*Conn)
+		var err error
+		info, err = c.Info()
+		if err != nil {
+			return err
+		}
+		quote, err = c.InfoString(api.SQL_IDENTIFIER_QUOTE_CHAR)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.DBMSName != "Microsoft SQL Server" {
+		t.Errorf("unexpected DBMS name %q", info.DBMSName)
+	}
+	if info.IdentifierQuoteChar != `"` || quote != `"` {
+		t.Errorf("unexpected identifier quote char %q and %q", info.IdentifierQuoteChar, quote)
+	}
+	if info.MaxColumnNameLen != 128 {
+		t.Errorf("unexpected max column name length %d", info.MaxColumnNameLen)
+	}
+	if info.TxnIsolationOptions&api.SQL_TXN_SERIALIZABLE == 0 {
+		t.Errorf("serializable isolation should be supported: %#x", info.TxnIsolationOptions)
+	}
+}
+
+func TestMSSQLCatalog(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp_child")
+	db.Exec("drop table dbo.temp_parent")
+	exec(t, db, "create table dbo.temp_parent (id int primary key, name varchar(50))")
+	defer exec(t, db, "drop table dbo.temp_parent")
+	exec(t, db, "create table dbo.temp_child (id int primary key, parent_id int references dbo.temp_parent(id))")
+	defer exec(t, db, "drop table dbo.temp_child")
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// column returns values of column name from all rows.
+	column := func(rows driver.Rows, name string) []string {
+		t.Helper()
+		defer rows.Close()
+		idx := -1
+		for i, n := range rows.Columns() {
+			if strings.EqualFold(n, name) {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			t.Fatalf("no %s column in %v", name, rows.Columns())
+		}
+		var vals []string
+		dest := make([]driver.Value, len(rows.Columns()))
+		for {
+			err := rows.Next(dest)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			vals = append(vals, fmt.Sprint(dest[idx]))
+		}
+		return vals
+	}
+	tests := []struct {
+		name   string
+		call   func(c *Conn) (driver.Rows, error)
+		column string
+		want   []string
+	}{
+		{
+			"Tables",
+			func(c *Conn) (driver.Rows, error) { return c.Tables(ctx, "", "dbo", "temp_p%", "TABLE") },
+			"TABLE_NAME",
+			[]string{"temp_parent"},
+		},
+		{
+			"Columns",
+			func(c *Conn) (driver.Rows, error) { return c.Columns(ctx, "", "dbo", "temp_parent", "") },
+			"COLUMN_NAME",
+			[]string{"id", "name"},
+		},
+		{
+			"PrimaryKeys",
+			func(c *Conn) (driver.Rows, error) { return c.PrimaryKeys(ctx, "", "dbo", "temp_child") },
+			"COLUMN_NAME",
+			[]string{"id"},
+		},
+		{
+			"ForeignKeys",
+			func(c *Conn) (driver.Rows, error) { return c.ForeignKeys(ctx, "", "dbo", "temp_parent", "", "", "") },
+			"FKCOLUMN_NAME",
+			[]string{"parent_id"},
+		},
+	}
+	for _, tc := range tests {
+		var got []string
+		err := conn.Raw(func(dc interface{}) error {
+			rows, err := tc.call(dc.(*Conn))
+			if err != nil {
+				return err
+			}
+			got = column(rows, tc.column)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s failed: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: want %q, got %q", tc.name, tc.want, got)
+		}
+	}
+
+	err = conn.Raw(func(dc interface{}) error {
+		rows, err := dc.(*Conn).TypeInfo(ctx, api.SQL_ALL_TYPES)
+		if err != nil {
+			return err
+		}
+		if names := column(rows, "TYPE_NAME"); len(names) == 0 {
+			t.Error("no types returned")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMSSQLOutputParams(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop procedure dbo.temp_out")
+	exec(t, db, `create procedure dbo.temp_out
+	@in int, @doubled int output, @name nvarchar(50) output, @counter bigint output
+as
+begin
+	select @in as a union all select @in + 1
+	set @doubled = @in * 2
+	set @name = N'name ' + @name
+	set @counter = @counter + 1
+end`)
+	defer exec(t, db, "drop procedure dbo.temp_out")
+
+	var doubled int
+	name := "abc"
+	counter := int64(41)
+	_, err = db.Exec("{call dbo.temp_out(?, ?, ?, ?)}", 21,
+		sql.Out{Dest: &doubled}, sql.Out{Dest: &name, In: true}, sql.Out{Dest: &counter, In: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doubled != 42 || name != "name abc" || counter != 42 {
+		t.Fatalf("unexpected output values: %d, %q, %d", doubled, name, counter)
+	}
+
+	// Output values are available after rows are closed.
+	doubled, name, counter = 0, "x", 0
+	rows, err := db.Query("{call dbo.temp_out(?, ?, ?, ?)}", 5,
+		sql.Out{Dest: &doubled}, sql.Out{Dest: &name, In: true}, sql.Out{Dest: &counter, In: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 rows, got %d", n)
+	}
+	if doubled != 10 || name != "name x" || counter != 1 {
+		t.Fatalf("unexpected output values: %d, %q, %d", doubled, name, counter)
+	}
+}
+
+func TestMSSQLNamedParams(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	var a, b string
+	err = db.QueryRow("select @first + :second, @first", sql.Named("second", "b"), sql.Named("first", "a")).Scan(&a, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != "ab" || b != "a" {
+		t.Fatalf("unexpected values: %q, %q", a, b)
+	}
+
+	// T-SQL variables are left alone when arguments are positional.
+	var n int
+	err = db.QueryRow("declare @x int; set @x = ?; select @x", 7).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 7 {
+		t.Fatalf("expected 7, got %d", n)
+	}
+	st, err := db.Prepare("declare @x int; set @x = ?; select @x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	if _, err := st.Exec(); err == nil || !strings.Contains(err.Error(), "expected 1 arguments") {
+		t.Fatalf("want argument count error, got %v", err)
+	}
+
+	// Procedure parameters are matched by name.
+	db.Exec("drop procedure dbo.temp_named")
+	exec(t, db, `create procedure dbo.temp_named @a int, @b int
+as
+begin
+	select @a - @b
+end`)
+	defer exec(t, db, "drop procedure dbo.temp_named")
+	err = db.QueryRow("{call dbo.temp_named(?, ?)}", sql.Named("b", 1), sql.Named("a", 10)).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 9 {
+		t.Fatalf("expected 9, got %d", n)
+	}
+}
+
+func TestMSSQLParamTypes(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	tests := []struct {
+		query string
+		arg   interface{}
+		want  string
+	}{
+		{"select cast(? as varchar(50))", int8(-8), "-8"},
+		{"select cast(? as varchar(50))", uint8(255), "255"},
+		{"select cast(? as varchar(50))", int16(-16), "-16"},
+		{"select cast(? as varchar(50))", uint16(65535), "65535"},
+		{"select cast(? as varchar(50))", int32(-32), "-32"},
+		{"select cast(? as varchar(50))", uint32(4294967295), "4294967295"},
+		{"select cast(? as varchar(50))", uint64(18446744073709551615), "18446744073709551615"},
+		{"select cast(? as varchar(50))", float32(1.5), "1.5"},
+		{"select lower(cast(? as varchar(50)))", [16]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10}, "01234567-89ab-cdef-fedc-ba9876543210"},
+		{"select cast(? as varchar(50))", new(big.Int).Lsh(big.NewInt(1), 100), "1267650600228229401496703205376"},
+		{"select cast(? as varchar(50))", big.NewFloat(-12.625), "-12.625"},
+		{"select cast(? as varchar(50))", Decimal{text: "12345678901234567890.123456789"}, "12345678901234567890.123456789"},
+	}
+	for _, test := range tests {
+		var got string
+		if err := db.QueryRow(test.query, test.arg).Scan(&got); err != nil {
+			t.Errorf("%T: %v", test.arg, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%T: want %q, got %q", test.arg, test.want, got)
+		}
+	}
+}
+
+func TestMSSQLDecimal(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	want, err := ParseDecimal("-12345678901234567890.123456789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d Decimal
+	var f float64
+	err = db.QueryRow("select cast(? as decimal(38, 9)), cast(? as decimal(38, 9))", want, want).Scan(&d, &f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != want {
+		t.Errorf("want %v, got %v", want, d)
+	}
+	if f != -12345678901234567890.123456789 {
+		t.Errorf("unexpected float64 value %v", f)
+	}
+
+	// Old behavior is available with DecimalFloat.
+	params := newConnParams()
+	params["GoODBC_DecimalMode"] = "float"
+	fdb, err := sql.Open("odbc", params.makeODBCConnectionString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fdb.Close()
+	var v interface{}
+	if err := fdb.QueryRow("select cast(1.5 as decimal(5, 2))").Scan(&v); err != nil {
+		t.Fatal(err)
+	}
+	if v != 1.5 {
+		t.Errorf("want float64 1.5, got %v (%T)", v, v)
+	}
+}
+
+func TestMSSQLExecBatch(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	exec(t, db, "create table dbo.temp (id int primary key, name nvarchar(50) null, price decimal(10, 2) null)")
+	defer exec(t, db, "drop table dbo.temp")
+
+	const n = 2500
+	rows := make([][]interface{}, n)
+	for i := range rows {
+		var name interface{}
+		if i%10 != 0 {
+			name = fmt.Sprintf("name %d", i)
+		}
+		rows[i] = []interface{}{i, name, Decimal{text: fmt.Sprintf("%d.25", i)}}
+	}
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	execBatch := func(rows [][]interface{}) (r driver.Result, err error) {
+		err = conn.Raw(func(dc interface{}) error {
+			st, err := dc.(*Conn).PrepareContext(ctx, "insert into dbo.temp (id, name, price) values (?, ?, ?)")
+			if err != nil {
+				return err
+			}
+			defer st.Close()
+			r, err = st.(*Stmt).ExecBatchContext(ctx, rows)
+			return err
+		})
+		return r, err
+	}
+	r, err := execBatch(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := r.RowsAffected(); c != n {
+		t.Errorf("expected %d rows affected, got %d", n, c)
+	}
+	var count int
+	if err := db.QueryRow("select count(*) from dbo.temp where name is not null").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != n-n/10 {
+		t.Errorf("expected %d rows with names, got %d", n-n/10, count)
+	}
+
+	// Duplicate key fails second row.
+	_, err = execBatch([][]interface{}{{n, "a", nil}, {0, "b", nil}, {n + 1, "c", nil}})
+	be, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("expected *BatchError, got %v", err)
+	}
+	if len(be.Failed) != 1 || be.Failed[0] != 1 {
+		t.Errorf("expected row 1 to fail, got %v", be.Failed)
+	}
+}
+
+func TestMSSQLFetchSize(t *testing.T) {
+	const query = `with n as (select 1 as i union all select i + 1 from n where i < 250)
+select i, case when i %% 7 = 0 then null else cast(i as varchar(10)) end, cast(i as decimal(10, 2)) / 4, %s from n
+option (maxrecursion 0)`
+
+	read := func(fetchSize string, last string) []string {
+		params := newConnParams()
+		params["GoODBC_FetchSize"] = fetchSize
+		db, err := sql.Open("odbc", params.makeODBCConnectionString())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		rows, err := db.Query(fmt.Sprintf(query, last))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var got []string
+		for rows.Next() {
+			var i int
+			var s sql.NullString
+			var d, l string
+			if err := rows.Scan(&i, &s, &d, &l); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, fmt.Sprintf("%d %v %s %s", i, s, d, l))
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	// Last column is bound, or it is long and read with SQLGetData.
+	for _, last := range []string{"N'abc'", "cast(replicate(N'x', 5000) as nvarchar(max))"} {
+		want := read("1", last)
+		if len(want) != 250 {
+			t.Fatalf("expected 250 rows, got %d", len(want))
+		}
+		for _, fetchSize := range []string{"0", "7", "1000"} {
+			got := read(fetchSize, last)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("fetch size %s returned different rows", fetchSize)
+			}
+		}
+	}
+}
+
+func TestMSSQLLOBReader(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	exec(t, db, "create table dbo.temp (id int, data varbinary(max) null, text nvarchar(max) null)")
+	defer exec(t, db, "drop table dbo.temp")
+
+	data := bytes.Repeat([]byte{0, 1, 2, 3, 4, 5, 6}, 300000)
+	text := strings.Repeat("abc\U0001F600", 20000)
+	if _, err := db.Exec("insert into dbo.temp (id, data, text) values (1, ?, ?), (2, null, null)", data, text); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	err = conn.Raw(func(dc interface{}) error {
+		st, err := dc.(*Conn).PrepareContext(ctx, "select id, data, text from dbo.temp order by id")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		dr, err := st.(*Stmt).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		rows := dr.(*Rows)
+		dest := make([]driver.Value, 3)
+
+		if err := rows.NextReaders(dest); err != nil {
+			return err
+		}
+		if dest[0] != int32(1) {
+			t.Errorf("expected id 1, got %v", dest[0])
+		}
+		// read in small pieces to cross chunk boundaries
+		var b bytes.Buffer
+		if _, err := io.CopyBuffer(&b, struct{ io.Reader }{dest[1].(*LOBReader)}, make([]byte, 1000)); err != nil {
+			return err
+		}
+		if !bytes.Equal(b.Bytes(), data) {
+			t.Errorf("data mismatch: got %d bytes, want %d", b.Len(), len(data))
+		}
+		b.Reset()
+		if _, err := io.CopyBuffer(&b, struct{ io.Reader }{dest[2].(*LOBReader)}, make([]byte, 1001)); err != nil {
+			return err
+		}
+		if b.String() != text {
+			t.Errorf("text mismatch: got %d bytes, want %d", b.Len(), len(text))
+		}
+		// reader of the previous column is no longer valid
+		if _, err := dest[1].(*LOBReader).Read(make([]byte, 10)); err != errLOBReaderInvalid {
+			t.Errorf("expected invalid reader error, got %v", err)
+		}
+		old := dest[2].(*LOBReader)
+
+		if err := rows.NextReaders(dest); err != nil {
+			return err
+		}
+		if _, err := old.Read(make([]byte, 10)); err != errLOBReaderInvalid {
+			t.Errorf("expected invalid reader error after next row, got %v", err)
+		}
+		for _, i := range []int{1, 2} {
+			r := dest[i].(*LOBReader)
+			if n, err := r.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+				t.Errorf("column %d: expected EOF for NULL, got %d, %v", i, n, err)
+			}
+			if !r.Null() {
+				t.Errorf("column %d: expected NULL", i)
+			}
+		}
+		if err := rows.NextReaders(dest); err != io.EOF {
+			t.Errorf("expected EOF, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMSSQLReaderParams(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	exec(t, db, "create table dbo.temp (id int, data varbinary(max) null)")
+	defer exec(t, db, "drop table dbo.temp")
+
+	data := bytes.Repeat([]byte{0, 1, 2, 3, 4, 5, 6}, 300000)
+	for i, r := range []io.Reader{bytes.NewReader(data), strings.NewReader(""), io.MultiReader(bytes.NewReader(data[:10]), bytes.NewReader(data[10:]))} {
+		if _, err := db.Exec("insert into dbo.temp (id, data) values (?, ?)", i, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, want := range [][]byte{data, {}, data} {
+		var got []byte
+		if err := db.QueryRow("select data from dbo.temp where id = ?", i).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("row %d: got %d bytes, want %d", i, len(got), len(want))
+		}
+	}
+
+	// Reader error cancels the statement.
+	readErr := errors.New("read failed")
+	r := io.MultiReader(bytes.NewReader(data), iotestErrReader{readErr})
+	if _, err := db.Exec("insert into dbo.temp (id, data) values (?, ?)", 3, r); err != readErr {
+		t.Errorf("expected %v, got %v", readErr, err)
+	}
+	var count int
+	if err := db.QueryRow("select count(*) from dbo.temp").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 rows, got %d", count)
+	}
+}
+
+// iotestErrReader returns err on every Read.
+type iotestErrReader struct{ err error }
+
+func (r iotestErrReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestMSSQLParamReuse(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	exec(t, db, "create table dbo.temp (id int, name nvarchar(max) null, data varbinary(max) null, ts datetime2 null)")
+	defer exec(t, db, "drop table dbo.temp")
+
+	st, err := db.Prepare("insert into dbo.temp (id, name, data, ts) values (?, ?, ?, ?)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	// Values change type and grow, so parameters are bound
+	// to the same buffers again and again, or rebound.
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := make(map[int]string)
+	for i := 0; i < 300; i++ {
+		name := interface{}(strings.Repeat("x", i%7))
+		if i%5 == 0 {
+			name = nil
+		}
+		var data interface{} = []byte(strings.Repeat("y", i))
+		if i%3 == 0 {
+			data = nil
+		}
+		if _, err := st.Exec(i, name, data, ts.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		want[i] = fmt.Sprintf("%v %v %d", name, data != nil, i)
+	}
+	rows, err := db.Query("select id, name, data, ts from dbo.temp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	n := 0
+	for rows.Next() {
+		var id int
+		var name sql.NullString
+		var data []byte
+		var got time.Time
+		if err := rows.Scan(&id, &name, &data, &got); err != nil {
+			t.Fatal(err)
+		}
+		var nameValue interface{}
+		if name.Valid {
+			nameValue = name.String
+		}
+		if s := fmt.Sprintf("%v %v %d", nameValue, data != nil, int(got.Sub(ts)/time.Second)); s != want[id] {
+			t.Errorf("row %d: want %q, got %q", id, want[id], s)
+		}
+		if data != nil && len(data) != id {
+			t.Errorf("row %d: want %d bytes, got %d", id, id, len(data))
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 300 {
+		t.Errorf("expected 300 rows, got %d", n)
+	}
+}
+
+func TestMSSQLNextRowset(t *testing.T) {
+	db, sc, err := mssqlConnect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	query := "select top 250 cast(row_number() over (order by (select null)) as int) as i, " +
+		"cast(case when row_number() over (order by (select null)) % 3 = 0 then null else N'abc' end as nvarchar(10)) as s, " +
+		"cast(12.5 as decimal(10, 2)) as d from sys.all_objects"
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	err = conn.Raw(func(dc interface{}) error {
+		st, err := dc.(*Conn).PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		dr, err := st.(*Stmt).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		rows := dr.(*Rows)
+		if p, s, ok := rows.ColumnTypePrecisionScale(2); !ok || p != 10 || s != 2 {
+			t.Errorf("expected decimal(10, 2), got %d, %d, %v", p, s, ok)
+		}
+		if l, ok := rows.ColumnTypeLength(1); !ok || l != 10 {
+			t.Errorf("expected nvarchar length 10, got %d, %v", l, ok)
+		}
+		if rows.ColumnCType(0) != api.SQL_C_LONG || rows.ColumnSQLType(0) != api.SQL_INTEGER {
+			t.Errorf("unexpected types of column 0: %d, %d", rows.ColumnCType(0), rows.ColumnSQLType(0))
+		}
+		total, rowsets := 0, 0
+		for {
+			n, err := rows.NextRowset()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			rowsets++
+			for row := 0; row < n; row++ {
+				total++
+				b, err := rows.RawValue(0, row)
+				if err != nil {
+					return err
+				}
+				i := *(*int32)(unsafe.Pointer(&b[0]))
+				if int(i) != total {
+					t.Fatalf("expected row %d, got %d", total, i)
+				}
+				b, err = rows.RawValue(1, row)
+				if err != nil {
+					return err
+				}
+				if (b == nil) != (i%3 == 0) {
+					t.Errorf("row %d: unexpected NULL state of %v", i, b)
+				}
+				b, err = rows.RawValue(2, row)
+				if err != nil {
+					return err
+				}
+				if string(b) != "12.50" {
+					t.Errorf("row %d: expected 12.50, got %q", i, b)
+				}
+			}
+		}
+		if total != 250 {
+			t.Errorf("expected 250 rows, got %d", total)
+		}
+		if rowsets != 3 {
+			t.Errorf("expected 3 rowsets of up to 100 rows, got %d", rowsets)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMSSQLScrollableCursor(t *testing.T) {
+	params := newConnParams()
+	params["GoODBC_CursorType"] = "static"
+	db, sc, err := mssqlConnectWithParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeDB(t, db, sc, sc)
+
+	db.Exec("drop table dbo.temp")
+	if _, err := db.Exec("create table dbo.temp (id int primary key, name varchar(10))"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("drop table dbo.temp")
+	for i := 1; i <= 10; i++ {
+		if _, err := db.Exec("insert into dbo.temp (id, name) values (?, ?)", i, fmt.Sprintf("n%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	err = conn.Raw(func(dc interface{}) error {
+		st, err := dc.(*Conn).PrepareContext(ctx, "select id, name from dbo.temp order by id")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		dr, err := st.(*Stmt).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		rows := dr.(*Rows)
+		dest := make([]driver.Value, 2)
+		for _, step := range []struct {
+			name string
+			move func() error
+			id   int
+		}{
+			{"Last", func() error { return rows.Last(dest) }, 10},
+			{"Prior", func() error { return rows.Prior(dest) }, 9},
+			{"First", func() error { return rows.First(dest) }, 1},
+			{"Next", func() error { return rows.Next(dest) }, 2},
+			{"Absolute(5)", func() error { return rows.Absolute(5, dest) }, 5},
+			{"Relative(3)", func() error { return rows.Relative(3, dest) }, 8},
+			{"Relative(-2)", func() error { return rows.Relative(-2, dest) }, 6},
+			{"Absolute(-3)", func() error { return rows.Absolute(-3, dest) }, 8},
+		} {
+			if err := step.move(); err != nil {
+				return fmt.Errorf("%s: %v", step.name, err)
+			}
+			got := fmt.Sprintf("%v %s", dest[0], dest[1])
+			if want := fmt.Sprintf("%d n%d", step.id, step.id); got != want {
+				t.Errorf("%s: want %q, got %q", step.name, want, got)
+			}
+		}
+		if err := rows.Absolute(11, dest); err != io.EOF {
+			t.Errorf("Absolute(11): want io.EOF, got %v", err)
+		}
+		if err := rows.First(dest); err != nil {
+			return err
+		}
+		if err := rows.Prior(dest); err != io.EOF {
+			t.Errorf("Prior before first row: want io.EOF, got %v", err)
+		}
+
+		// Statements without result set keep forward-only cursor.
+		ust, err := dc.(*Conn).PrepareContext(ctx, "update dbo.temp set name = name where id = ?")
+		if err != nil {
+			return err
+		}
+		defer ust.Close()
+		if _, err := ust.(*Stmt).ExecContext(ctx, []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}); err != nil {
+			return err
+		}
+		if ct := ust.(*Stmt).os.cursorType; ct != CursorForwardOnly {
+			t.Errorf("Exec used %v cursor", ct)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Scrolling needs scrollable cursor.
+	fdb, err := sql.Open("odbc", newConnParams().makeODBCConnectionString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fdb.Close()
+	fconn, err := fdb.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fconn.Close()
+	err = fconn.Raw(func(dc interface{}) error {
+		st, err := dc.(*Conn).PrepareContext(ctx, "select 1")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		dr, err := st.(*Stmt).QueryContext(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		if err := dr.(*Rows).Last(make([]driver.Value, 1)); err != errForwardOnly {
+			t.Errorf("want %v, got %v", errForwardOnly, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
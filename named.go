@@ -0,0 +1,162 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// placeholder is a parameter marker found in a query:
+// ? (with empty name), @name or :name.
+type placeholder struct {
+	start, end int // position in query
+	name       string
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentChar(r rune) bool {
+	return r == '_' || r == '$' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scanPlaceholders returns parameter markers of query. String
+// literals, quoted identifiers (in double quotes, brackets or
+// backticks) and comments are skipped. @@name (SQL Server globals) and :: (PostgreSQL casts)
+// are not markers.
+func scanPlaceholders(query string) []placeholder {
+	var phs []placeholder
+	skipTo := func(i int, end string) int {
+		j := strings.Index(query[i:], end)
+		if j < 0 {
+			return len(query)
+		}
+		return i + j + len(end)
+	}
+	prev := rune(0)
+	for i := 0; i < len(query); {
+		r, n := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case r == '\'' || r == '"' || r == '`':
+			// doubled quote inside literal is handled as
+			// two literals next to each other
+			i = skipTo(i+1, string(r))
+			prev = r
+			continue
+		case r == '[':
+			i = skipTo(i+1, "]")
+			prev = ']'
+			continue
+		case strings.HasPrefix(query[i:], "--"):
+			i = skipTo(i, "\n")
+			prev = '\n'
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			i = skipTo(i+2, "*/")
+			prev = ' '
+			continue
+		case r == '?':
+			phs = append(phs, placeholder{start: i, end: i + 1})
+		case (r == '@' || r == ':') && prev != r && !isIdentChar(prev):
+			j := i + n
+			first, _ := utf8.DecodeRuneInString(query[j:])
+			if !isIdentStart(first) {
+				break
+			}
+			for j < len(query) {
+				c, m := utf8.DecodeRuneInString(query[j:])
+				if !isIdentChar(c) {
+					break
+				}
+				j += m
+			}
+			phs = append(phs, placeholder{start: i, end: j, name: query[i+n : j]})
+			prev = 'a'
+			i = j
+			continue
+		}
+		prev = r
+		i += n
+	}
+	return phs
+}
+
+// allNamedPlaceholders reports whether phs are all @name or :name.
+// Named markers next to ? are taken for variables of the query,
+// like T-SQL @local or MySQL @uservar, rather than parameters.
+func allNamedPlaceholders(phs []placeholder) bool {
+	for _, ph := range phs {
+		if ph.name == "" {
+			return false
+		}
+	}
+	return len(phs) > 0
+}
+
+// bindNamed replaces placeholders phs of query with ? and returns
+// new query together with args ordered to match its markers.
+// Name used more than once in query gets its argument repeated.
+func bindNamed(query string, phs []placeholder, args []driver.NamedValue) (string, []driver.Value, error) {
+	byName := make(map[string]int, len(args))
+	for i, a := range args {
+		byName[a.Name] = i
+	}
+	used := make([]bool, len(args))
+	var b strings.Builder
+	vs := make([]driver.Value, 0, len(phs))
+	last := 0
+	for _, ph := range phs {
+		if ph.name == "" {
+			return "", nil, errors.New("odbc: query mixes ? and named parameter markers")
+		}
+		i, ok := byName[ph.name]
+		if !ok {
+			return "", nil, fmt.Errorf("odbc: no value for named parameter %q", ph.name)
+		}
+		used[i] = true
+		vs = append(vs, args[i].Value)
+		b.WriteString(query[last:ph.start])
+		b.WriteByte('?')
+		last = ph.end
+	}
+	b.WriteString(query[last:])
+	for i, u := range used {
+		if !u {
+			return "", nil, fmt.Errorf("odbc: named parameter %q is not used in query", args[i].Name)
+		}
+	}
+	return b.String(), vs, nil
+}
+
+// nameParam sets name of parameter idx in implementation parameter
+// descriptor of s, so the driver matches it to procedure parameter
+// by name rather than by position.
+func (s *ODBCStmt) nameParam(idx int, name string) error {
+	var ipd api.SQLHDESC
+	ret := api.SQLGetStmtAttr(s.h, api.SQL_ATTR_IMP_PARAM_DESC, api.SQLPOINTER(unsafe.Pointer(&ipd)), api.SQL_IS_POINTER, nil)
+	if IsError(ret) {
+		return NewError("SQLGetStmtAttr", s.h)
+	}
+	rec := api.SQLSMALLINT(idx + 1)
+	ret = api.SQLSetDescUIntPtrField(ipd, rec, api.SQL_DESC_UNNAMED, api.SQL_NAMED, 0)
+	if IsError(ret) {
+		return NewError("SQLSetDescField", ipd)
+	}
+	b := api.StringToUTF16(s.conn.dialect.ParamNamePrefix + name)
+	ret = api.SQLSetDescField(ipd, rec, api.SQL_DESC_NAME, api.SQLPOINTER(unsafe.Pointer(&b[0])), api.SQL_NTS)
+	if IsError(ret) {
+		return NewError("SQLSetDescField", ipd)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package odbc
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestScanPlaceholders(t *testing.T) {
+	tests := []struct {
+		query string
+		names []string
+	}{
+		{"select 1", nil},
+		{"select ?, ?", []string{"", ""}},
+		{"select @a, :b_2 from t where c = @a", []string{"a", "b_2", "a"}},
+		{"select '@a', \"@b\", [@c], `:d` from t", nil},
+		{"select 'it''s @a', @b", []string{"b"}},
+		{"select @@rowcount, x::int, a := 1", nil},
+		{"select 1 -- @a\n, @b /* :c */", []string{"b"}},
+		{"select a@b, :1", nil},
+		{"select @ä", []string{"ä"}},
+	}
+	for _, test := range tests {
+		var names []string
+		for _, ph := range scanPlaceholders(test.query) {
+			names = append(names, ph.name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("scanPlaceholders(%q): want %q, got %q", test.query, test.names, names)
+		}
+	}
+}
+
+func TestAllNamedPlaceholders(t *testing.T) {
+	tests := []struct {
+		query string
+		named bool
+	}{
+		{"select 1", false},
+		{"select ?", false},
+		{"select @a, :b", true},
+		{"declare @x int; set @x = ?; select @x", false},
+	}
+	for _, test := range tests {
+		if named := allNamedPlaceholders(scanPlaceholders(test.query)); named != test.named {
+			t.Errorf("allNamedPlaceholders(%q): want %v, got %v", test.query, test.named, named)
+		}
+	}
+}
+
+func TestBindNamed(t *testing.T) {
+	query := "select @a, :b from t where c = @a"
+	args := []driver.NamedValue{
+		{Name: "b", Ordinal: 1, Value: "x"},
+		{Name: "a", Ordinal: 2, Value: int64(1)},
+	}
+	q, vs, err := bindNamed(query, scanPlaceholders(query), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "select ?, ? from t where c = ?"; q != want {
+		t.Errorf("want %q, got %q", want, q)
+	}
+	if want := []driver.Value{int64(1), "x", int64(1)}; !reflect.DeepEqual(vs, want) {
+		t.Errorf("want %v, got %v", want, vs)
+	}
+
+	for _, query := range []string{
+		"select @a, ?",
+		"select @a",     // b is not used
+		"select @a, @c", // c has no value
+	} {
+		if _, _, err := bindNamed(query, scanPlaceholders(query), args); err == nil {
+			t.Errorf("bindNamed(%q) should fail", query)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbcarrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// column describes how values of result column are stored in Arrow.
+type column struct {
+	sqltype   api.SQLSMALLINT
+	ctype     api.SQLSMALLINT
+	precision int64 // decimal precision
+	scale     int64 // decimal scale or fractional second digits
+}
+
+// appendFunc appends value v, as stored in ODBC buffer, to builder b.
+type appendFunc func(b array.Builder, v []byte) error
+
+// arrowType returns Arrow type of c values, and function,
+// that appends them to builder of that type.
+func (c column) arrowType() (arrow.DataType, appendFunc, error) {
+	switch c.ctype {
+	case api.SQL_C_BIT:
+		return arrow.FixedWidthTypes.Boolean, func(b array.Builder, v []byte) error {
+			b.(*array.BooleanBuilder).Append(v[0] != 0)
+			return nil
+		}, nil
+	case api.SQL_C_LONG:
+		if c.sqltype == api.SQL_SMALLINT || c.sqltype == api.SQL_TINYINT {
+			return arrow.PrimitiveTypes.Int16, func(b array.Builder, v []byte) error {
+				b.(*array.Int16Builder).Append(int16(*(*int32)(unsafe.Pointer(&v[0]))))
+				return nil
+			}, nil
+		}
+		return arrow.PrimitiveTypes.Int32, func(b array.Builder, v []byte) error {
+			b.(*array.Int32Builder).Append(*(*int32)(unsafe.Pointer(&v[0])))
+			return nil
+		}, nil
+	case api.SQL_C_SBIGINT:
+		return arrow.PrimitiveTypes.Int64, func(b array.Builder, v []byte) error {
+			b.(*array.Int64Builder).Append(*(*int64)(unsafe.Pointer(&v[0])))
+			return nil
+		}, nil
+	case api.SQL_C_DOUBLE:
+		return arrow.PrimitiveTypes.Float64, func(b array.Builder, v []byte) error {
+			b.(*array.Float64Builder).Append(*(*float64)(unsafe.Pointer(&v[0])))
+			return nil
+		}, nil
+	case api.SQL_C_CHAR:
+		if (c.sqltype == api.SQL_NUMERIC || c.sqltype == api.SQL_DECIMAL) &&
+			0 < c.precision && c.precision <= 38 {
+			t := &arrow.Decimal128Type{Precision: int32(c.precision), Scale: int32(c.scale)}
+			return t, func(b array.Builder, v []byte) error {
+				n, err := decimal128.FromString(string(v), t.Precision, t.Scale)
+				if err != nil {
+					return fmt.Errorf("odbcarrow: invalid decimal %q: %v", v, err)
+				}
+				b.(*array.Decimal128Builder).Append(n)
+				return nil
+			}, nil
+		}
+		return arrow.BinaryTypes.String, func(b array.Builder, v []byte) error {
+			b.(*array.StringBuilder).BinaryBuilder.Append(v)
+			return nil
+		}, nil
+	case api.SQL_C_WCHAR:
+		var buf []byte
+		return arrow.BinaryTypes.String, func(b array.Builder, v []byte) error {
+			buf = appendUTF16(buf[:0], v)
+			b.(*array.StringBuilder).BinaryBuilder.Append(buf)
+			return nil
+		}, nil
+	case api.SQL_C_BINARY:
+		if c.sqltype == api.SQL_SS_TIME2 {
+			return arrow.FixedWidthTypes.Time64ns, func(b array.Builder, v []byte) error {
+				t := (*api.SQL_SS_TIME2_STRUCT)(unsafe.Pointer(&v[0]))
+				d := time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+					time.Duration(t.Second)*time.Second + time.Duration(t.Fraction)
+				b.(*array.Time64Builder).Append(arrow.Time64(d))
+				return nil
+			}, nil
+		}
+		return arrow.BinaryTypes.Binary, func(b array.Builder, v []byte) error {
+			b.(*array.BinaryBuilder).Append(v)
+			return nil
+		}, nil
+	case api.SQL_C_TYPE_TIMESTAMP:
+		// Values have no time zone, so are stored as wall clock time.
+		unit := arrow.Microsecond
+		if c.scale > 6 {
+			unit = arrow.Nanosecond
+		}
+		return &arrow.TimestampType{Unit: unit}, func(b array.Builder, v []byte) error {
+			t := (*api.SQL_TIMESTAMP_STRUCT)(unsafe.Pointer(&v[0]))
+			tm := time.Date(int(t.Year), time.Month(t.Month), int(t.Day),
+				int(t.Hour), int(t.Minute), int(t.Second), int(t.Fraction), time.UTC)
+			ts, err := arrow.TimestampFromTime(tm, unit)
+			if err != nil {
+				return fmt.Errorf("odbcarrow: timestamp %v: %v", tm, err)
+			}
+			b.(*array.TimestampBuilder).Append(ts)
+			return nil
+		}, nil
+	case api.SQL_C_DATE:
+		return arrow.FixedWidthTypes.Date32, func(b array.Builder, v []byte) error {
+			t := (*api.SQL_DATE_STRUCT)(unsafe.Pointer(&v[0]))
+			tm := time.Date(int(t.Year), time.Month(t.Month), int(t.Day), 0, 0, 0, 0, time.UTC)
+			b.(*array.Date32Builder).Append(arrow.Date32FromTime(tm))
+			return nil
+		}, nil
+	case api.SQL_C_TIME:
+		return arrow.FixedWidthTypes.Time32s, func(b array.Builder, v []byte) error {
+			t := (*api.SQL_TIME_STRUCT)(unsafe.Pointer(&v[0]))
+			s := int32(t.Hour)*3600 + int32(t.Minute)*60 + int32(t.Second)
+			b.(*array.Time32Builder).Append(arrow.Time32(s))
+			return nil
+		}, nil
+	case api.SQL_C_GUID:
+		// Stored in RFC 4122 byte order.
+		var u [16]byte
+		return &arrow.FixedSizeBinaryType{ByteWidth: 16}, func(b array.Builder, v []byte) error {
+			g := (*api.SQLGUID)(unsafe.Pointer(&v[0]))
+			binary.BigEndian.PutUint32(u[0:4], *(*uint32)(unsafe.Pointer(&g.Data1)))
+			binary.BigEndian.PutUint16(u[4:6], *(*uint16)(unsafe.Pointer(&g.Data2)))
+			binary.BigEndian.PutUint16(u[6:8], *(*uint16)(unsafe.Pointer(&g.Data3)))
+			copy(u[8:], (*[8]byte)(unsafe.Pointer(&g.Data4))[:])
+			b.(*array.FixedSizeBinaryBuilder).Append(u[:])
+			return nil
+		}, nil
+	}
+	return nil, nil, fmt.Errorf("odbcarrow: unsupported column C type %d (SQL type %d)", c.ctype, c.sqltype)
+}
+
+// appendUTF16 appends UTF-8 encoding of UTF-16 text b to dst.
+// Invalid surrogates are replaced with U+FFFD.
+func appendUTF16(dst []byte, b []byte) []byte {
+	n := len(b) / 2
+	if n == 0 {
+		return dst
+	}
+	s := unsafe.Slice((*uint16)(unsafe.Pointer(&b[0])), n)
+	for i := 0; i < n; i++ {
+		r := rune(s[i])
+		if utf16.IsSurrogate(r) {
+			if i+1 < n {
+				if d := utf16.DecodeRune(r, rune(s[i+1])); d != utf8.RuneError {
+					dst = utf8.AppendRune(dst, d)
+					i++
+					continue
+				}
+			}
+			r = utf8.RuneError
+		}
+		dst = utf8.AppendRune(dst, r)
+	}
+	return dst
+}
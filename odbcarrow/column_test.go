@@ -0,0 +1,113 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbcarrow
+
+import (
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/sigmacomputing/odbc/api"
+)
+
+func rawBytes(p unsafe.Pointer, n uintptr) []byte {
+	return append([]byte(nil), unsafe.Slice((*byte)(p), n)...)
+}
+
+func utf16Bytes(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	if len(u) == 0 {
+		return []byte{}
+	}
+	return rawBytes(unsafe.Pointer(&u[0]), uintptr(2*len(u)))
+}
+
+func TestColumnArrowType(t *testing.T) {
+	i32 := int32(-7)
+	i64 := int64(1) << 40
+	f64 := 2.5
+	ts := api.SQL_TIMESTAMP_STRUCT{Year: 2021, Month: 3, Day: 4, Hour: 5, Minute: 6, Second: 7, Fraction: 123456789}
+	date := api.SQL_DATE_STRUCT{Year: 1969, Month: 12, Day: 31}
+	tm := api.SQL_TIME_STRUCT{Hour: 1, Minute: 2, Second: 3}
+	tm2 := api.SQL_SS_TIME2_STRUCT{Hour: 1, Minute: 2, Second: 3, Fraction: 400}
+	var guid api.SQLGUID
+	*(*uint32)(unsafe.Pointer(&guid.Data1)) = 0x01234567
+	*(*uint16)(unsafe.Pointer(&guid.Data2)) = 0x89ab
+	*(*uint16)(unsafe.Pointer(&guid.Data3)) = 0xcdef
+	copy((*[8]byte)(unsafe.Pointer(&guid.Data4))[:], []byte{0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10})
+
+	tests := []struct {
+		c    column
+		v    []byte
+		typ  arrow.DataType
+		want string
+	}{
+		{column{sqltype: api.SQL_BIT, ctype: api.SQL_C_BIT}, []byte{1}, arrow.FixedWidthTypes.Boolean, "true"},
+		{column{sqltype: api.SQL_SMALLINT, ctype: api.SQL_C_LONG}, rawBytes(unsafe.Pointer(&i32), 4), arrow.PrimitiveTypes.Int16, "-7"},
+		{column{sqltype: api.SQL_INTEGER, ctype: api.SQL_C_LONG}, rawBytes(unsafe.Pointer(&i32), 4), arrow.PrimitiveTypes.Int32, "-7"},
+		{column{sqltype: api.SQL_BIGINT, ctype: api.SQL_C_SBIGINT}, rawBytes(unsafe.Pointer(&i64), 8), arrow.PrimitiveTypes.Int64, "1099511627776"},
+		{column{sqltype: api.SQL_DOUBLE, ctype: api.SQL_C_DOUBLE}, rawBytes(unsafe.Pointer(&f64), 8), arrow.PrimitiveTypes.Float64, "2.5"},
+		{column{sqltype: api.SQL_DECIMAL, ctype: api.SQL_C_CHAR, precision: 10, scale: 2}, []byte("-12.34"), &arrow.Decimal128Type{Precision: 10, Scale: 2}, "-12.34"},
+		{column{sqltype: api.SQL_DECIMAL, ctype: api.SQL_C_CHAR, precision: 10, scale: 2}, []byte(".50"), &arrow.Decimal128Type{Precision: 10, Scale: 2}, "0.5"},
+		{column{sqltype: api.SQL_DECIMAL, ctype: api.SQL_C_CHAR}, []byte("1.5"), arrow.BinaryTypes.String, "1.5"},
+		{column{sqltype: api.SQL_VARCHAR, ctype: api.SQL_C_CHAR}, []byte("abc"), arrow.BinaryTypes.String, "abc"},
+		{column{sqltype: api.SQL_WVARCHAR, ctype: api.SQL_C_WCHAR}, utf16Bytes("hé\U0001F600"), arrow.BinaryTypes.String, "hé\U0001F600"},
+		{column{sqltype: api.SQL_VARBINARY, ctype: api.SQL_C_BINARY}, []byte{1, 2}, arrow.BinaryTypes.Binary, "AQI="},
+		{column{sqltype: api.SQL_TYPE_TIMESTAMP, ctype: api.SQL_C_TYPE_TIMESTAMP, scale: 3}, rawBytes(unsafe.Pointer(&ts), unsafe.Sizeof(ts)), &arrow.TimestampType{Unit: arrow.Microsecond}, "2021-03-04T05:06:07.123456"},
+		{column{sqltype: api.SQL_TYPE_TIMESTAMP, ctype: api.SQL_C_TYPE_TIMESTAMP, scale: 7}, rawBytes(unsafe.Pointer(&ts), unsafe.Sizeof(ts)), &arrow.TimestampType{Unit: arrow.Nanosecond}, "2021-03-04T05:06:07.123456789"},
+		{column{sqltype: api.SQL_TYPE_DATE, ctype: api.SQL_C_DATE}, rawBytes(unsafe.Pointer(&date), unsafe.Sizeof(date)), arrow.FixedWidthTypes.Date32, "1969-12-31"},
+		{column{sqltype: api.SQL_TYPE_TIME, ctype: api.SQL_C_TIME}, rawBytes(unsafe.Pointer(&tm), unsafe.Sizeof(tm)), arrow.FixedWidthTypes.Time32s, "01:02:03"},
+		{column{sqltype: api.SQL_SS_TIME2, ctype: api.SQL_C_BINARY}, rawBytes(unsafe.Pointer(&tm2), unsafe.Sizeof(tm2)), arrow.FixedWidthTypes.Time64ns, "01:02:03.000000400"},
+		{column{sqltype: api.SQL_GUID, ctype: api.SQL_C_GUID}, rawBytes(unsafe.Pointer(&guid), unsafe.Sizeof(guid)), &arrow.FixedSizeBinaryType{ByteWidth: 16}, "ASNFZ4mrze/+3LqYdlQyEA=="},
+	}
+	mem := memory.NewGoAllocator()
+	for _, test := range tests {
+		typ, fn, err := test.c.arrowType()
+		if err != nil {
+			t.Errorf("%+v: %v", test.c, err)
+			continue
+		}
+		if !arrow.TypeEqual(typ, test.typ) {
+			t.Errorf("%+v: want type %v, got %v", test.c, test.typ, typ)
+			continue
+		}
+		b := array.NewBuilder(mem, typ)
+		if err := fn(b, test.v); err != nil {
+			t.Errorf("%+v: %v", test.c, err)
+			b.Release()
+			continue
+		}
+		b.AppendNull()
+		a := b.NewArray()
+		if got := a.ValueStr(0); got != test.want {
+			t.Errorf("%+v: want %q, got %q", test.c, test.want, got)
+		}
+		if !a.IsNull(1) {
+			t.Errorf("%+v: second value should be NULL", test.c)
+		}
+		a.Release()
+		b.Release()
+	}
+	if _, _, err := (column{ctype: 12345}).arrowType(); err == nil {
+		t.Error("unsupported C type should fail")
+	}
+}
+
+func TestAppendUTF16(t *testing.T) {
+	for _, s := range []string{"", "abc", "\U0001F600x", "世界"} {
+		if got := string(appendUTF16(nil, utf16Bytes(s))); got != s {
+			t.Errorf("want %q, got %q", s, got)
+		}
+	}
+	// lone surrogates
+	u := []uint16{0xd800, 'a', 0xdc00}
+	b := rawBytes(unsafe.Pointer(&u[0]), 6)
+	if got := string(appendUTF16(nil, b)); got != "�a�" {
+		t.Errorf("lone surrogates: got %q", got)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package odbcarrow reads query results of github.com/sigmacomputing/odbc
+// as Apache Arrow record batches. Values are copied from ODBC buffers
+// directly into Arrow builders, without conversion into driver.Value.
+//
+// It is a separate module, so the odbc package itself does not
+// depend on Arrow.
+package odbcarrow
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/sigmacomputing/odbc"
+)
+
+// DefaultBatchRows is number of rows in record batches,
+// if NewReader is called with batchRows 0.
+const DefaultBatchRows = 4096
+
+// Reader reads rows of odbc.Rows as Arrow record batches.
+// It implements array.RecordReader. Reader does not close
+// the rows.
+type Reader struct {
+	refs      atomic.Int64
+	rows      *odbc.Rows
+	schema    *arrow.Schema
+	bld       *array.RecordBuilder
+	appends   []appendFunc
+	batchRows int
+	cur       arrow.RecordBatch
+	n, pos    int // rows in current rowset and next row to read
+	done      bool
+	err       error
+}
+
+var _ array.RecordReader = (*Reader)(nil)
+
+// NewReader returns Reader of current result set of rows. Its schema is
+// derived from column descriptions: SQL type, precision and scale, and
+// nullability. Record batches have up to batchRows rows, DefaultBatchRows
+// if batchRows is 0. Rows should be fetched with block cursor, see
+// odbc.Config.FetchSize, or columns are filled one row at a time.
+func NewReader(rows *odbc.Rows, mem memory.Allocator, batchRows int) (*Reader, error) {
+	if batchRows <= 0 {
+		batchRows = DefaultBatchRows
+	}
+	names := rows.Columns()
+	fields := make([]arrow.Field, len(names))
+	appends := make([]appendFunc, len(names))
+	for i, name := range names {
+		c := column{
+			sqltype: rows.ColumnSQLType(i),
+			ctype:   rows.ColumnCType(i),
+		}
+		c.precision, c.scale, _ = rows.ColumnTypePrecisionScale(i)
+		t, fn, err := c.arrowType()
+		if err != nil {
+			return nil, fmt.Errorf("%v: column %q", err, name)
+		}
+		nullable, ok := rows.ColumnTypeNullable(i)
+		fields[i] = arrow.Field{
+			Name:     name,
+			Type:     t,
+			Nullable: nullable || !ok,
+			Metadata: arrow.NewMetadata([]string{"odbc.type"}, []string{rows.ColumnTypeDatabaseTypeName(i)}),
+		}
+		appends[i] = fn
+	}
+	r := &Reader{
+		rows:      rows,
+		schema:    arrow.NewSchema(fields, nil),
+		appends:   appends,
+		batchRows: batchRows,
+	}
+	r.bld = array.NewRecordBuilder(mem, r.schema)
+	r.refs.Store(1)
+	return r, nil
+}
+
+// Retain increases reference count of r.
+func (r *Reader) Retain() {
+	r.refs.Add(1)
+}
+
+// Release decreases reference count of r, and releases
+// its memory, when the count reaches 0.
+func (r *Reader) Release() {
+	if r.refs.Add(-1) != 0 {
+		return
+	}
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	r.bld.Release()
+}
+
+// Schema returns schema of record batches.
+func (r *Reader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads next record batch. It returns false at the end
+// of result set or on error, see Err. Previous record batch
+// is released, so it has to be retained to be used after Next.
+func (r *Reader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.err != nil || r.done {
+		return false
+	}
+	count := 0
+	for count < r.batchRows {
+		if r.pos >= r.n {
+			n, err := r.rows.NextRowset()
+			if err == io.EOF {
+				r.done = true
+				break
+			}
+			if err != nil {
+				r.err = err
+				return false
+			}
+			r.n, r.pos = n, 0
+		}
+		k := r.n - r.pos
+		if k > r.batchRows-count {
+			k = r.batchRows - count
+		}
+		if err := r.appendRows(r.pos, k); err != nil {
+			r.err = err
+			return false
+		}
+		r.pos += k
+		count += k
+	}
+	if count == 0 {
+		return false
+	}
+	r.cur = r.bld.NewRecordBatch()
+	return true
+}
+
+// appendRows appends k rows of current rowset, starting with row pos.
+func (r *Reader) appendRows(pos, k int) error {
+	for i, fn := range r.appends {
+		b := r.bld.Field(i)
+		for row := pos; row < pos+k; row++ {
+			v, err := r.rows.RawValue(i, row)
+			if err != nil {
+				return err
+			}
+			if v == nil {
+				b.AppendNull()
+				continue
+			}
+			if err := fn(b, v); err != nil {
+				return fmt.Errorf("%v: column %q", err, r.schema.Field(i).Name)
+			}
+		}
+	}
+	return nil
+}
+
+// RecordBatch returns record batch read by last Next.
+func (r *Reader) RecordBatch() arrow.RecordBatch {
+	return r.cur
+}
+
+// Record returns record batch read by last Next.
+//
+// Deprecated: Use RecordBatch instead.
+func (r *Reader) Record() arrow.RecordBatch {
+	return r.cur
+}
+
+// Err returns error, that stopped Next, if any.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Query runs query with args on conn and calls fn with every record
+// batch of its first result set. Record batch is released after fn
+// returns, fn has to retain it to keep it longer.
+func Query(ctx context.Context, conn *sql.Conn, query string, fn func(arrow.RecordBatch) error, args ...interface{}) error {
+	return conn.Raw(func(dc interface{}) error {
+		c, ok := dc.(*odbc.Conn)
+		if !ok {
+			return errors.New("odbcarrow: connection is not odbc connection")
+		}
+		nvs, err := namedValues(c, args)
+		if err != nil {
+			return err
+		}
+		st, err := c.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+		dr, err := st.(*odbc.Stmt).QueryContext(ctx, nvs)
+		if err != nil {
+			return err
+		}
+		defer dr.Close()
+		r, err := NewReader(dr.(*odbc.Rows), memory.DefaultAllocator, 0)
+		if err != nil {
+			return err
+		}
+		defer r.Release()
+		for r.Next() {
+			if err := fn(r.RecordBatch()); err != nil {
+				return err
+			}
+		}
+		return r.Err()
+	})
+}
+
+// namedValues converts args, like database/sql does, for driver connection c.
+func namedValues(c *odbc.Conn, args []interface{}) ([]driver.NamedValue, error) {
+	nvs := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		nv := driver.NamedValue{Ordinal: i + 1, Value: a}
+		if na, ok := a.(sql.NamedArg); ok {
+			nv.Name, nv.Value = na.Name, na.Value
+		}
+		err := c.CheckNamedValue(&nv)
+		if err == driver.ErrSkip {
+			nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("odbcarrow: argument %d: %v", i+1, err)
+		}
+		nvs[i] = nv
+	}
+	return nvs, nil
+}
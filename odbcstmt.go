@@ -5,9 +5,11 @@
 package odbc
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 	"unsafe"
@@ -19,44 +21,83 @@ import (
 
 type ODBCStmt struct {
 	h          api.SQLHSTMT
+	conn       *Conn
 	loc        *time.Location
 	Parameters []Parameter
 	Cols       []Column
+	// paramNames, if set, are names Parameters are bound by.
+	paramNames []string
+	// queryTimeout is current SQL_ATTR_QUERY_TIMEOUT value in seconds.
+	queryTimeout uintptr
+	// Block cursor state. rowStatus is set, if Cols are bound
+	// to arrays of rows, see bindRowset. rowsFetched has single
+	// element, the number of rows in fetched rowset. Both are
+	// allocated separately, because memory passed to C must not
+	// contain Go pointers. rowIdx is current row in fetched rowset.
+	rowStatus   []api.SQLUSMALLINT
+	rowsFetched []api.SQLULEN
+	rowIdx      int
+	// cursorType is the cursor s was prepared with.
+	cursorType CursorType
 	// locking/lifetime
 	mu         sync.Mutex
 	usedByStmt bool
 	usedByRows bool
 }
 
-func (c *Conn) PrepareODBCStmt(query string) (*ODBCStmt, error) {
+// allocStmtHandle allocates new statement handle on c.
+func (c *Conn) allocStmtHandle() (api.SQLHSTMT, error) {
 	var out api.SQLHANDLE
 	ret := api.SQLAllocHandle(api.SQL_HANDLE_STMT, api.SQLHANDLE(c.h), &out)
 	if IsError(ret) {
-		return nil, c.newError("SQLAllocHandle", c.h)
+		return api.SQLHSTMT(api.SQL_NULL_HSTMT), c.newError("SQLAllocHandle", c.h)
 	}
 	h := api.SQLHSTMT(out)
-	err := drv.Stats.updateHandleCount(api.SQL_HANDLE_STMT, 1)
+	err := c.drv.Stats.updateHandleCount(api.SQL_HANDLE_STMT, 1)
+	if err != nil {
+		return api.SQLHSTMT(api.SQL_NULL_HSTMT), err
+	}
+	return h, nil
+}
+
+func (c *Conn) PrepareODBCStmt(query string) (*ODBCStmt, error) {
+	return c.prepareODBCStmt(query, c.cfg.CursorType)
+}
+
+// prepareODBCStmt prepares query to return results with cursor of type ct.
+func (c *Conn) prepareODBCStmt(query string, ct CursorType) (*ODBCStmt, error) {
+	h, err := c.allocStmtHandle()
 	if err != nil {
 		return nil, err
 	}
+	if ct != CursorForwardOnly {
+		if err := c.setCursorType(h, ct); err != nil {
+			defer c.drv.releaseHandle(h)
+			return nil, err
+		}
+	}
 
 	b := api.StringToUTF16(query)
-	ret = api.SQLPrepare(h, (*api.SQLWCHAR)(unsafe.Pointer(&b[0])), api.SQL_NTS)
+	ret := api.SQLPrepare(h, (*api.SQLWCHAR)(unsafe.Pointer(&b[0])), api.SQL_NTS)
 	if IsError(ret) {
-		defer releaseHandle(h)
+		defer c.drv.releaseHandle(h)
 		return nil, c.newError("SQLPrepare", h)
 	}
 	ps, err := ExtractParameters(h)
 	if err != nil {
-		defer releaseHandle(h)
+		defer c.drv.releaseHandle(h)
 		return nil, err
 	}
-	return &ODBCStmt{
+	s := &ODBCStmt{
 		h:          h,
+		conn:       c,
 		loc:        c.loc,
 		Parameters: ps,
+		cursorType: ct,
 		usedByStmt: true,
-	}, nil
+	}
+	c.addStmt(s)
+	return s, nil
 }
 
 func (s *ODBCStmt) closeByStmt() error {
@@ -76,7 +117,7 @@ func (s *ODBCStmt) closeByRows() error {
 	defer s.mu.Unlock()
 	if s.usedByRows {
 		defer func() { s.usedByRows = false }()
-		if s.usedByStmt {
+		if s.usedByStmt && s.h != api.SQLHSTMT(api.SQL_NULL_HSTMT) {
 			ret := api.SQLCloseCursor(s.h)
 			if IsError(ret) {
 				return NewError("SQLCloseCursor", s.h)
@@ -89,10 +130,67 @@ func (s *ODBCStmt) closeByRows() error {
 	return nil
 }
 
+// closeAll frees s even if it is still used by Stmt or Rows.
+// It is called when connection of s is closed.
+func (s *ODBCStmt) closeAll() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usedByStmt = false
+	s.usedByRows = false
+	return s.releaseHandle()
+}
+
 func (s *ODBCStmt) releaseHandle() error {
 	h := s.h
+	if h == api.SQLHSTMT(api.SQL_NULL_HSTMT) {
+		return nil
+	}
 	s.h = api.SQLHSTMT(api.SQL_NULL_HSTMT)
-	return releaseHandle(h)
+	s.conn.removeStmt(s)
+	return s.conn.drv.releaseHandle(h)
+}
+
+// watchCancel calls SQLCancel on s if ctx is done
+// before the returned stop function is called.
+func (s *ODBCStmt) watchCancel(ctx context.Context) (stop func()) {
+	h := s.h
+	return watchContext(ctx, func() {
+		api.SQLCancel(h)
+	})
+}
+
+// setQueryTimeout sets SQL_ATTR_QUERY_TIMEOUT of s to time left
+// before ctx deadline or to def, whichever is shorter.
+func (s *ODBCStmt) setQueryTimeout(ctx context.Context, def time.Duration) error {
+	d := def
+	if deadline, ok := ctx.Deadline(); ok {
+		d = time.Until(deadline)
+		if d <= 0 {
+			return context.DeadlineExceeded
+		}
+		if def > 0 && def < d {
+			d = def
+		}
+	}
+	secs := uintptr(0)
+	if d > 0 {
+		secs = durationToSeconds(d)
+	}
+	if secs == s.queryTimeout {
+		return nil
+	}
+	ret := api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_QUERY_TIMEOUT, secs, api.SQL_IS_UINTEGER)
+	if IsError(ret) {
+		err := NewError("SQLSetStmtUIntPtrAttr", s.h)
+		if e, ok := err.(*Error); ok && e.hasState("HYC00") {
+			// Driver does not support query timeouts. Rely
+			// on SQLCancel to stop the query instead.
+			return nil
+		}
+		return err
+	}
+	s.queryTimeout = secs
+	return nil
 }
 
 var testingIssue5 bool // used during tests
@@ -102,29 +200,114 @@ func (s *ODBCStmt) Exec(args []driver.Value, conn *Conn) error {
 		return fmt.Errorf("wrong number of arguments %d, %d expected", len(args), len(s.Parameters))
 	}
 	for i, a := range args {
-		// this could be done in 2 steps:
-		// 1) bind vars right after prepare;
-		// 2) set their (vars) values here;
-		// but rebinding parameters for every new parameter value
-		// should be efficient enough for our purpose.
+		// BindValue stores new value in buffer of the parameter,
+		// and only calls SQLBindParameter, if buffer changes.
 		if err := s.Parameters[i].BindValue(s.h, i, a, conn); err != nil {
 			return err
 		}
+		if s.paramNames != nil {
+			if err := s.nameParam(i, s.paramNames[i]); err != nil {
+				return err
+			}
+		}
 	}
 	if testingIssue5 {
 		time.Sleep(10 * time.Microsecond)
 	}
+	apiName := "SQLExecute"
 	ret := api.SQLExecute(s.h)
+	if ret == api.SQL_NEED_DATA {
+		var err error
+		if ret, err = s.putData(); err != nil {
+			return err
+		}
+		apiName = "SQLParamData"
+	}
 	if ret == api.SQL_NO_DATA {
 		// success but no data to report
 		return nil
 	}
 	if IsError(ret) {
-		return NewError("SQLExecute", s.h)
+		return NewError(apiName, s.h)
 	}
 	return nil
 }
 
+// putDataChunkSize is size of chunks sent by putData.
+const putDataChunkSize = 64 * 1024
+
+// putData sends values of io.Reader parameters, that driver asks
+// for after SQLExecute returned SQL_NEED_DATA. It returns result
+// of the final SQLParamData, that completes the execution.
+func (s *ODBCStmt) putData() (api.SQLRETURN, error) {
+	var buf []byte
+	for {
+		var token api.SQLPOINTER
+		ret := api.SQLParamData(s.h, &token)
+		if ret != api.SQL_NEED_DATA {
+			return ret, nil
+		}
+		var r io.Reader
+		for i := range s.Parameters {
+			p := &s.Parameters[i]
+			if d, ok := p.Data.(io.Reader); ok && p.dataAtExecToken() == token {
+				r = d
+				break
+			}
+		}
+		if r == nil {
+			api.SQLCancel(s.h)
+			return 0, errors.New("odbc: driver asked for data of unknown parameter")
+		}
+		if buf == nil {
+			buf = make([]byte, putDataChunkSize)
+		}
+		sent := false
+		for {
+			n, err := r.Read(buf)
+			// empty value still has to be sent once
+			if n > 0 || err == io.EOF && !sent {
+				ret := api.SQLPutData(s.h, api.SQLPOINTER(unsafe.Pointer(&buf[0])), api.SQLLEN(n))
+				if IsError(ret) {
+					err := NewError("SQLPutData", s.h)
+					api.SQLCancel(s.h)
+					return 0, err
+				}
+				sent = true
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				api.SQLCancel(s.h)
+				return 0, err
+			}
+		}
+	}
+}
+
+// rowsAffected returns number of rows affected by executed
+// statement s, summed over all its results.
+func (s *ODBCStmt) rowsAffected() (int64, error) {
+	var sum int64
+	for {
+		var c api.SQLLEN
+		ret := api.SQLRowCount(s.h, &c)
+		if IsError(ret) {
+			return 0, NewError("SQLRowCount", s.h)
+		}
+		sum += int64(c)
+		if ret = api.SQLMoreResults(s.h); ret == api.SQL_NO_DATA {
+			break
+		}
+	}
+	return sum, nil
+}
+
+// defaultFetchSize is the number of rows fetched at once,
+// if Config.FetchSize is not set.
+const defaultFetchSize = 100
+
 func (s *ODBCStmt) BindColumns() error {
 	// count columns
 	var n api.SQLSMALLINT
@@ -135,15 +318,42 @@ func (s *ODBCStmt) BindColumns() error {
 	if n < 1 {
 		return errors.New("Stmt did not create a result set")
 	}
+	// forget buffers bound for previous result set
+	ret = api.SQLFreeStmt(s.h, api.SQL_UNBIND)
+	if IsError(ret) {
+		return NewError("SQLFreeStmt", s.h)
+	}
 	// fetch column descriptions
 	s.Cols = make([]Column, n)
-	binding := true
+	allBindable := true
 	for i := range s.Cols {
-		c, err := NewColumn(s.h, i, s.loc)
+		c, err := newColumn(s.h, i, s.loc, s.conn.dialect, s.conn.cfg.DecimalMode)
 		if err != nil {
 			return err
 		}
 		s.Cols[i] = c
+		if _, ok := c.(*BindableColumn); !ok {
+			allBindable = false
+		}
+	}
+	fetchSize := s.conn.cfg.FetchSize
+	if fetchSize == 0 {
+		fetchSize = defaultFetchSize
+	}
+	// Long columns are read with SQLGetData, which
+	// works only with one row fetched at a time.
+	// Scrollable cursors move one row at a time too.
+	if fetchSize > 1 && allBindable && s.cursorType == CursorForwardOnly {
+		ok, err := s.bindRowset(fetchSize)
+		if err != nil || ok {
+			return err
+		}
+	}
+	if err := s.resetRowset(); err != nil {
+		return err
+	}
+	binding := true
+	for i := range s.Cols {
 		// Once we found one non-bindable column, we will not bind the rest.
 		// http://www.easysoft.com/developer/languages/c/odbc-tutorial-fetching-results.html
 		// ... One common restriction is that SQLGetData may only be called on columns after the last bound column. ...
@@ -160,3 +370,97 @@ func (s *ODBCStmt) BindColumns() error {
 	}
 	return nil
 }
+
+// setStmtPtrAttr sets statement attribute attr to pointer p.
+func (s *ODBCStmt) setStmtPtrAttr(attr api.SQLINTEGER, p unsafe.Pointer) error {
+	ret := api.SQLSetStmtAttr(s.h, attr, api.SQLPOINTER(p), 0)
+	if IsError(ret) {
+		return NewError("SQLSetStmtAttr", s.h)
+	}
+	return nil
+}
+
+// bindRowset binds all Cols of s to arrays of n rows, so SQLFetch
+// returns n rows at once. It reports false, if the driver does not
+// support block cursors.
+func (s *ODBCStmt) bindRowset(n int) (bool, error) {
+	ret := api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_ROW_ARRAY_SIZE, uintptr(n), api.SQL_IS_UINTEGER)
+	if IsError(ret) {
+		err := NewError("SQLSetStmtUIntPtrAttr", s.h)
+		if isUnsupportedAttr(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if ret == api.SQL_SUCCESS_WITH_INFO {
+		// Driver might have used smaller rowset.
+		var v api.SQLULEN
+		ret = api.SQLGetStmtAttr(s.h, api.SQL_ATTR_ROW_ARRAY_SIZE, api.SQLPOINTER(unsafe.Pointer(&v)), 0, nil)
+		if !IsError(ret) && v >= 1 && int(v) < n {
+			n = int(v)
+		}
+	}
+	s.rowStatus = make([]api.SQLUSMALLINT, n)
+	if err := s.setStmtPtrAttr(api.SQL_ATTR_ROW_STATUS_PTR, unsafe.Pointer(&s.rowStatus[0])); err != nil {
+		return false, err
+	}
+	s.rowsFetched = make([]api.SQLULEN, 1)
+	if err := s.setStmtPtrAttr(api.SQL_ATTR_ROWS_FETCHED_PTR, unsafe.Pointer(&s.rowsFetched[0])); err != nil {
+		return false, err
+	}
+	for i, c := range s.Cols {
+		if err := c.(*BindableColumn).bindArray(s.h, i, n); err != nil {
+			return false, err
+		}
+	}
+	s.rowIdx = 0
+	return true, nil
+}
+
+// resetRowset makes s fetch one row at a time.
+func (s *ODBCStmt) resetRowset() error {
+	if s.rowStatus == nil {
+		return nil
+	}
+	ret := api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_ROW_ARRAY_SIZE, 1, api.SQL_IS_UINTEGER)
+	if IsError(ret) {
+		return NewError("SQLSetStmtUIntPtrAttr", s.h)
+	}
+	if err := s.setStmtPtrAttr(api.SQL_ATTR_ROW_STATUS_PTR, nil); err != nil {
+		return err
+	}
+	if err := s.setStmtPtrAttr(api.SQL_ATTR_ROWS_FETCHED_PTR, nil); err != nil {
+		return err
+	}
+	s.rowStatus = nil
+	s.rowsFetched = nil
+	return nil
+}
+
+// fetchRowsetRow moves s to next row of the rowset,
+// fetching next rowset when needed.
+func (s *ODBCStmt) fetchRowsetRow() error {
+	for {
+		s.rowIdx++
+		if s.rowIdx >= int(s.rowsFetched[0]) {
+			ret := api.SQLFetch(s.h)
+			if ret == api.SQL_NO_DATA {
+				return io.EOF
+			}
+			if IsError(ret) {
+				return NewError("SQLFetch", s.h)
+			}
+			if s.rowsFetched[0] == 0 {
+				return io.EOF
+			}
+			s.rowIdx = 0
+		}
+		switch s.rowStatus[s.rowIdx] {
+		case api.SQL_ROW_NOROW:
+			continue
+		case api.SQL_ROW_ERROR:
+			return fmt.Errorf("odbc: driver failed to fetch row %d of rowset", s.rowIdx)
+		}
+		return nil
+	}
+}
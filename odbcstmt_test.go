@@ -0,0 +1,61 @@
+package odbc
+
+import (
+	"testing"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+func TestNewVariableWidthColumn(t *testing.T) {
+	tests := []struct {
+		width    api.SQLULEN
+		bindable bool
+	}{
+		{0, false},
+		{1, true},
+		{maxBindableWidth, true},
+		{maxBindableWidth + 1, false},
+	}
+	for _, test := range tests {
+		c, err := NewVariableWidthColumn(&BaseColumn{name: "s"}, api.SQL_C_WCHAR, test.width)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Only bindable columns are fetched in blocks.
+		if _, ok := c.(*BindableColumn); ok != test.bindable {
+			t.Errorf("column of width %d: want bindable %v, got %T", test.width, test.bindable, c)
+		}
+	}
+}
+
+func TestBindRowset(t *testing.T) {
+	s := &ODBCStmt{h: api.SQLHSTMT(api.SQL_NULL_HSTMT)}
+	if IsError(api.SQLSetStmtUIntPtrAttr(s.h, api.SQL_ATTR_ROW_ARRAY_SIZE, 1, api.SQL_IS_UINTEGER)) {
+		t.Skip("statement attributes can not be set without driver")
+	}
+	s.Cols = []Column{
+		NewBindableColumn(&BaseColumn{name: "i"}, api.SQL_C_LONG, 4),
+		NewBindableColumn(&BaseColumn{name: "f"}, api.SQL_C_DOUBLE, 8),
+	}
+	ok, err := s.bindRowset(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("bindRowset should succeed")
+	}
+	if len(s.rowStatus) != 10 || len(s.rowsFetched) != 1 {
+		t.Fatalf("unexpected rowset state: %d row statuses, %d counters", len(s.rowStatus), len(s.rowsFetched))
+	}
+	for i, c := range s.Cols {
+		if b := c.(*BindableColumn); !b.IsBound || len(b.Lens) != 10 {
+			t.Errorf("column %d is not bound to array of 10 rows", i)
+		}
+	}
+	if err := s.resetRowset(); err != nil {
+		t.Fatal(err)
+	}
+	if s.rowStatus != nil || s.rowsFetched != nil {
+		t.Error("resetRowset should forget rowset buffers")
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// defaultOutChars is the buffer size, in characters or bytes, of string
+// and binary output parameters whose size is not known from SQLDescribeParam,
+// or is unlimited, like nvarchar(max).
+const defaultOutChars = 4000
+
+func checkOut(out sql.Out) error {
+	rv := reflect.ValueOf(out.Dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("odbc: sql.Out.Dest must be a non-nil pointer")
+	}
+	if _, err := outCType(rv.Elem().Type(), &Parameter{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// outCType returns C type used to bind output parameter p
+// with destination of type t.
+func outCType(t reflect.Type, p *Parameter) (api.SQLSMALLINT, error) {
+	if t == timeType {
+		return api.SQL_C_TYPE_TIMESTAMP, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return api.SQL_C_WCHAR, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return api.SQL_C_BINARY, nil
+		}
+	case reflect.Bool:
+		return api.SQL_C_BIT, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return api.SQL_C_SBIGINT, nil
+	case reflect.Float32, reflect.Float64:
+		return api.SQL_C_DOUBLE, nil
+	case reflect.Interface:
+		// Pick by parameter type.
+		switch p.SQLType {
+		case api.SQL_BIT:
+			return api.SQL_C_BIT, nil
+		case api.SQL_TINYINT, api.SQL_SMALLINT, api.SQL_INTEGER, api.SQL_BIGINT:
+			return api.SQL_C_SBIGINT, nil
+		case api.SQL_FLOAT, api.SQL_REAL, api.SQL_DOUBLE:
+			return api.SQL_C_DOUBLE, nil
+		case api.SQL_TYPE_TIMESTAMP, api.SQL_TYPE_DATE:
+			return api.SQL_C_TYPE_TIMESTAMP, nil
+		case api.SQL_BINARY, api.SQL_VARBINARY, api.SQL_LONGVARBINARY:
+			return api.SQL_C_BINARY, nil
+		}
+		return api.SQL_C_WCHAR, nil
+	}
+	return 0, fmt.Errorf("odbc: unsupported output parameter type %v", t)
+}
+
+// bindOut binds output or input/output parameter out.
+func (p *Parameter) bindOut(h api.SQLHSTMT, idx int, out sql.Out) error {
+	dest := reflect.ValueOf(out.Dest).Elem()
+	ctype, err := outCType(dest.Type(), p)
+	if err != nil {
+		return err
+	}
+	var in driver.Value
+	if out.In {
+		in, err = driver.DefaultParameterConverter.ConvertValue(dest.Interface())
+		if err != nil {
+			return err
+		}
+	}
+
+	var sqltype, decimal api.SQLSMALLINT
+	var size api.SQLULEN
+	var buf []byte
+	ind := api.SQLLEN(0)
+	if in == nil && out.In {
+		ind = api.SQL_NULL_DATA
+	}
+	switch ctype {
+	case api.SQL_C_WCHAR:
+		var s []uint16
+		if in != nil {
+			switch v := in.(type) {
+			case string:
+				s = api.StringToUTF16(v)
+			case []byte:
+				s = api.StringToUTF16(string(v))
+			default:
+				return fmt.Errorf("odbc: cannot use %T as string output parameter", in)
+			}
+			s = s[:len(s)-1] // remove terminating 0
+			ind = api.SQLLEN(2 * len(s))
+		}
+		n := defaultOutChars
+		if p.isDescribed && p.Size > 0 && p.Size < 1<<20 {
+			n = int(p.Size)
+		}
+		if len(s) > n {
+			n = len(s)
+		}
+		buf = make([]byte, 2*(n+1))
+		copy((*[1 << 29]uint16)(unsafe.Pointer(&buf[0]))[:n], s)
+		size = api.SQLULEN(n)
+		sqltype = api.SQL_WVARCHAR
+	case api.SQL_C_BINARY:
+		var b []byte
+		if in != nil {
+			v, ok := in.([]byte)
+			if !ok {
+				return fmt.Errorf("odbc: cannot use %T as binary output parameter", in)
+			}
+			b = v
+			ind = api.SQLLEN(len(b))
+		}
+		n := defaultOutChars
+		if p.isDescribed && p.Size > 0 && p.Size < 1<<20 {
+			n = int(p.Size)
+		}
+		if len(b) > n {
+			n = len(b)
+		}
+		buf = make([]byte, n)
+		copy(buf, b)
+		size = api.SQLULEN(n)
+		sqltype = api.SQL_VARBINARY
+	case api.SQL_C_BIT:
+		buf = make([]byte, 1)
+		if in != nil {
+			v, ok := in.(bool)
+			if !ok {
+				return fmt.Errorf("odbc: cannot use %T as bool output parameter", in)
+			}
+			if v {
+				buf[0] = 1
+			}
+		}
+		size = 1
+		sqltype = api.SQL_BIT
+	case api.SQL_C_SBIGINT:
+		buf = make([]byte, 8)
+		if in != nil {
+			v, ok := in.(int64)
+			if !ok {
+				return fmt.Errorf("odbc: cannot use %T as integer output parameter", in)
+			}
+			*(*int64)(unsafe.Pointer(&buf[0])) = v
+		}
+		size = 8
+		sqltype = api.SQL_BIGINT
+	case api.SQL_C_DOUBLE:
+		buf = make([]byte, 8)
+		if in != nil {
+			switch v := in.(type) {
+			case float64:
+				*(*float64)(unsafe.Pointer(&buf[0])) = v
+			case int64:
+				*(*float64)(unsafe.Pointer(&buf[0])) = float64(v)
+			default:
+				return fmt.Errorf("odbc: cannot use %T as float output parameter", in)
+			}
+		}
+		size = 8
+		sqltype = api.SQL_DOUBLE
+	case api.SQL_C_TYPE_TIMESTAMP:
+		var ts api.SQL_TIMESTAMP_STRUCT
+		buf = make([]byte, unsafe.Sizeof(ts))
+		if in != nil {
+			v, ok := in.(time.Time)
+			if !ok {
+				return fmt.Errorf("odbc: cannot use %T as time output parameter", in)
+			}
+			y, m, d := v.Date()
+			*(*api.SQL_TIMESTAMP_STRUCT)(unsafe.Pointer(&buf[0])) = api.SQL_TIMESTAMP_STRUCT{
+				Year:     api.SQLSMALLINT(y),
+				Month:    api.SQLUSMALLINT(m),
+				Day:      api.SQLUSMALLINT(d),
+				Hour:     api.SQLUSMALLINT(v.Hour()),
+				Minute:   api.SQLUSMALLINT(v.Minute()),
+				Second:   api.SQLUSMALLINT(v.Second()),
+				Fraction: api.SQLUINTEGER(v.Nanosecond()),
+			}
+		}
+		decimal = 3
+		if p.isDescribed && p.SQLType == api.SQL_TYPE_TIMESTAMP && p.Decimal > 0 {
+			decimal = p.Decimal
+		}
+		size = 20 + api.SQLULEN(decimal)
+		sqltype = api.SQL_TYPE_TIMESTAMP
+	}
+	if p.isDescribed {
+		sqltype = p.SQLType
+		if ctype != api.SQL_C_TYPE_TIMESTAMP {
+			decimal = p.Decimal
+		}
+		if p.Size > 0 && ctype != api.SQL_C_WCHAR && ctype != api.SQL_C_BINARY {
+			size = p.Size
+		}
+	}
+
+	iotype := api.SQLSMALLINT(api.SQL_PARAM_OUTPUT)
+	if out.In {
+		iotype = api.SQL_PARAM_INPUT_OUTPUT
+	}
+	p.Data = buf
+	p.out = &outParam{dest: dest, ctype: ctype, sqltype: sqltype}
+	ret := api.SQLBindParameter(h, api.SQLUSMALLINT(idx+1),
+		iotype, ctype, sqltype, size, decimal,
+		api.SQLPOINTER(unsafe.Pointer(&buf[0])), api.SQLLEN(len(buf)),
+		p.StoreStrLen_or_IndPtr(ind))
+	if IsError(ret) {
+		return NewError("SQLBindParameter", h)
+	}
+	return nil
+}
+
+// outParam remembers where to store value of output parameter.
+type outParam struct {
+	dest    reflect.Value
+	ctype   api.SQLSMALLINT
+	sqltype api.SQLSMALLINT
+}
+
+// copyOut stores value returned by the driver in output
+// parameter p into destination of sql.Out.
+func (p *Parameter) copyOut(loc *time.Location) error {
+	o := p.out
+	p.out = nil
+	if p.StrLen_or_IndPtr == api.SQL_NULL_DATA {
+		o.dest.Set(reflect.Zero(o.dest.Type()))
+		return nil
+	}
+	buf := p.Data.([]byte)
+	n := int(p.StrLen_or_IndPtr)
+	switch o.ctype {
+	case api.SQL_C_WCHAR:
+		if n > len(buf)-2 || n == api.SQL_NO_TOTAL {
+			return errors.New("odbc: output parameter value is truncated")
+		}
+		buf = buf[:n]
+	case api.SQL_C_BINARY:
+		if n > len(buf) || n == api.SQL_NO_TOTAL {
+			return errors.New("odbc: output parameter value is truncated")
+		}
+		buf = buf[:n]
+	}
+	if o.ctype == api.SQL_C_WCHAR && len(buf) == 0 {
+		return setOutDest(o.dest, "")
+	}
+	c := &BaseColumn{loc: loc, SQLType: o.sqltype, CType: o.ctype}
+	v, err := c.Value(buf)
+	if err != nil {
+		return err
+	}
+	if b, ok := v.([]byte); ok {
+		// buf is reused by next execution.
+		v = append([]byte(nil), b...)
+	}
+	return setOutDest(o.dest, v)
+}
+
+// setOutDest stores v into dest converting it, if needed.
+func setOutDest(dest reflect.Value, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	switch {
+	case dest.Kind() == reflect.Interface || rv.Type().AssignableTo(dest.Type()):
+		dest.Set(rv)
+		return nil
+	case dest.Kind() >= reflect.Int && dest.Kind() <= reflect.Int64 && rv.Kind() == reflect.Int64:
+		if dest.OverflowInt(rv.Int()) {
+			return fmt.Errorf("odbc: output parameter value %d overflows %v", rv.Int(), dest.Type())
+		}
+		dest.SetInt(rv.Int())
+		return nil
+	case dest.Kind() >= reflect.Uint && dest.Kind() <= reflect.Uint64 && rv.Kind() == reflect.Int64:
+		if rv.Int() < 0 || dest.OverflowUint(uint64(rv.Int())) {
+			return fmt.Errorf("odbc: output parameter value %d overflows %v", rv.Int(), dest.Type())
+		}
+		dest.SetUint(uint64(rv.Int()))
+		return nil
+	case rv.Type().ConvertibleTo(dest.Type()):
+		dest.Set(rv.Convert(dest.Type()))
+		return nil
+	}
+	return fmt.Errorf("odbc: cannot store %T output parameter value into %v", v, dest.Type())
+}
+
+// hasOutParams reports whether s has output parameters waiting
+// to be copied.
+func (s *ODBCStmt) hasOutParams() bool {
+	for i := range s.Parameters {
+		if s.Parameters[i].out != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// finishOutParams skips remaining results of s, so the driver
+// returns output parameter values, and copies them to their destinations.
+func (s *ODBCStmt) finishOutParams() error {
+	if s.h == api.SQLHSTMT(api.SQL_NULL_HSTMT) || !s.hasOutParams() {
+		return nil
+	}
+	for {
+		ret := api.SQLMoreResults(s.h)
+		if ret == api.SQL_NO_DATA {
+			break
+		}
+		if IsError(ret) {
+			return NewError("SQLMoreResults", s.h)
+		}
+	}
+	return s.copyOutParams()
+}
+
+// copyOutParams copies output parameter values to their destinations.
+// It must be called after all results of s are consumed.
+func (s *ODBCStmt) copyOutParams() error {
+	var err error
+	for i := range s.Parameters {
+		p := &s.Parameters[i]
+		if p.out == nil {
+			continue
+		}
+		if e := p.copyOut(s.loc); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package odbc
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetOutDest(t *testing.T) {
+	var (
+		s   string
+		i   int
+		i8  int8
+		u16 uint16
+		f32 float32
+		b   []byte
+		any interface{}
+		tm  time.Time
+	)
+	now := time.Now()
+	tests := []struct {
+		dest interface{}
+		v    interface{}
+		want interface{}
+		fail bool
+	}{
+		{&s, "hello", "hello", false},
+		{&s, []byte("bytes"), "bytes", false},
+		{&i, int64(42), 42, false},
+		{&i8, int64(-100), int8(-100), false},
+		{&i8, int64(300), nil, true},
+		{&u16, int64(65535), uint16(65535), false},
+		{&u16, int64(-1), nil, true},
+		{&f32, float64(1.5), float32(1.5), false},
+		{&b, []byte{1, 2}, []byte{1, 2}, false},
+		{&any, int64(7), int64(7), false},
+		{&tm, now, now, false},
+		{&i, "text", nil, true},
+	}
+	for _, tc := range tests {
+		dest := reflect.ValueOf(tc.dest).Elem()
+		err := setOutDest(dest, tc.v)
+		if tc.fail {
+			if err == nil {
+				t.Errorf("storing %v into %v should fail", tc.v, dest.Type())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("storing %v into %v failed: %v", tc.v, dest.Type(), err)
+			continue
+		}
+		if got := dest.Interface(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("storing %v into %v: want %v, got %v", tc.v, dest.Type(), tc.want, got)
+		}
+	}
+}
+
+func TestCheckOut(t *testing.T) {
+	var s string
+	var m map[string]int
+	for _, tc := range []struct {
+		out  sql.Out
+		fail bool
+	}{
+		{sql.Out{Dest: &s}, false},
+		{sql.Out{Dest: s}, true},
+		{sql.Out{Dest: (*string)(nil)}, true},
+		{sql.Out{Dest: &m}, true},
+	} {
+		err := checkOut(tc.out)
+		if tc.fail && err == nil {
+			t.Errorf("checkOut(%#v) should fail", tc.out)
+		}
+		if !tc.fail && err != nil {
+			t.Errorf("checkOut(%#v) failed: %v", tc.out, err)
+		}
+	}
+}
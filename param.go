@@ -5,9 +5,16 @@
 package odbc
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
 	"time"
+	"unicode/utf16"
 	"unsafe"
 
 	"github.com/sigmacomputing/odbc/api"
@@ -22,6 +29,24 @@ type Parameter struct {
 	// The fields keep data alive and away from gc.
 	Data             interface{}
 	StrLen_or_IndPtr api.SQLLEN
+	// out is set, if parameter is bound as output parameter.
+	out *outParam
+	// buf holds input value. It is reused by following executions.
+	buf []byte
+	// bound records last SQLBindParameter call, so parameter is
+	// only bound again, if any of its arguments change.
+	bound paramBinding
+	// token is bound as buffer of io.Reader values, see dataAtExecToken.
+	token []byte
+}
+
+// paramBinding is the set of SQLBindParameter arguments, that
+// describe parameter buffer. Zero value means not bound.
+type paramBinding struct {
+	ctype, sqltype, decimal api.SQLSMALLINT
+	size                    api.SQLULEN
+	buf                     unsafe.Pointer
+	buflen                  api.SQLLEN
 }
 
 // StoreStrLen_or_IndPtr stores v into StrLen_or_IndPtr field of p
@@ -32,30 +57,99 @@ func (p *Parameter) StoreStrLen_or_IndPtr(v api.SQLLEN) *api.SQLLEN {
 
 }
 
+// CheckNamedValue implements driver.NamedValueChecker. Besides
+// types produced by database/sql default converter, it accepts:
+//
+//	int8, int16, int32, uint8, uint16, uint32, uint, uint64
+//	float32
+//	[16]byte, sent as GUID, in RFC 4122 byte order
+//	Decimal, *big.Int and *big.Float, sent as DECIMAL without loss of precision
+//	DecimalStringer, sent as DECIMAL parsed from its DecimalString
+//	io.Reader, sent as binary data in chunks during execution
+//	sql.Out, bound as output or input/output parameter
+func (c *Conn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch v := nv.Value.(type) {
+	case sql.Out:
+		return checkOut(v)
+	case int8, int16, int32, uint8, uint16, uint32, uint64, float32, [16]byte, *big.Int, *big.Float, Decimal:
+		return nil
+	case *Decimal:
+		if v == nil {
+			nv.Value = nil
+		} else {
+			nv.Value = *v
+		}
+		return nil
+	case uint:
+		nv.Value = uint64(v)
+		return nil
+	case DecimalStringer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			nv.Value = nil
+			return nil
+		}
+		d, err := ParseDecimal(v.DecimalString())
+		if err != nil {
+			return err
+		}
+		nv.Value = d
+		return nil
+	case io.Reader:
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+// guidFromBytes converts UUID b, in RFC 4122 byte order, into SQLGUID.
+func guidFromBytes(b [16]byte) api.SQLGUID {
+	var g api.SQLGUID
+	// Fields of SQLGUID have C types on unix, so set them via pointers.
+	*(*uint32)(unsafe.Pointer(&g.Data1)) = binary.BigEndian.Uint32(b[0:4])
+	*(*uint16)(unsafe.Pointer(&g.Data2)) = binary.BigEndian.Uint16(b[4:6])
+	*(*uint16)(unsafe.Pointer(&g.Data3)) = binary.BigEndian.Uint16(b[6:8])
+	copy((*[8]byte)(unsafe.Pointer(&g.Data4))[:], b[8:])
+	return g
+}
+
 func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Conn) error {
-	// TODO(brainman): Reuse memory for previously bound values. If memory
-	// is reused, we, probably, do not need to call SQLBindParameter either.
 	var ctype, sqltype, decimal api.SQLSMALLINT
 	var size api.SQLULEN
-	var buflen api.SQLLEN
-	var plen *api.SQLLEN
+	var buflen, ind api.SQLLEN
 	var buf unsafe.Pointer
+	p.out = nil
+	p.Data = nil
 	switch d := v.(type) {
+	case sql.Out:
+		p.bound = paramBinding{}
+		return p.bindOut(h, idx, d)
+	case *big.Int:
+		if d == nil {
+			return p.BindValue(h, idx, nil, conn)
+		}
+		return p.BindValue(h, idx, Decimal{text: d.String()}, conn)
+	case *big.Float:
+		if d == nil {
+			return p.BindValue(h, idx, nil, conn)
+		}
+		if d.IsInf() {
+			return fmt.Errorf("odbc: cannot send %v *big.Float", d)
+		}
+		return p.BindValue(h, idx, Decimal{text: d.Text('f', -1)}, conn)
 	case nil:
 		ctype = api.SQL_C_WCHAR
-		p.Data = nil
 		buf = nil
 		size = 1
 		buflen = 0
-		plen = p.StoreStrLen_or_IndPtr(api.SQL_NULL_DATA)
+		ind = api.SQL_NULL_DATA
 		sqltype = api.SQL_WCHAR
 	case string:
+		if conn.cfg.StringMode == StringNarrow {
+			ctype, sqltype, size, buflen, buf = p.bindNarrowString(d, conn)
+			break
+		}
 		ctype = api.SQL_C_WCHAR
-		b := api.StringToUTF16(d)
-		p.Data = b
-		buf = unsafe.Pointer(&b[0])
-		l := len(b)
-		l -= 1 // remove terminating 0
+		l := p.putUTF16(d)
+		buf = unsafe.Pointer(&p.buf[0])
 		size = api.SQLULEN(l)
 		if size < 1 {
 			// size cannot be less then 1 even for empty fields
@@ -63,8 +157,7 @@ func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Con
 		}
 		l *= 2 // every char takes 2 bytes
 		buflen = api.SQLLEN(l)
-		plen = p.StoreStrLen_or_IndPtr(buflen)
-		if !conn.isMSAccessDriver {
+		if !conn.dialect.LongStringParams {
 			switch {
 			case size >= 4000:
 				sqltype = api.SQL_WLONGVARCHAR
@@ -85,39 +178,122 @@ func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Con
 			// Some ODBC drivers do not support SQL_BIGINT.
 			// Use SQL_INTEGER if the value fit in int32.
 			// See issue #78 for details.
-			d2 := int32(d)
+			buf, buflen = p.fixed(4)
+			*(*int32)(buf) = int32(d)
 			ctype = api.SQL_C_LONG
-			p.Data = &d2
-			buf = unsafe.Pointer(&d2)
 			sqltype = api.SQL_INTEGER
 			size = 4
 		} else {
+			buf, buflen = p.fixed(8)
+			*(*int64)(buf) = d
 			ctype = api.SQL_C_SBIGINT
-			p.Data = &d
-			buf = unsafe.Pointer(&d)
 			sqltype = api.SQL_BIGINT
 			size = 8
 		}
+	case int8:
+		// SQL Server TINYINT is unsigned, so use SMALLINT.
+		buf, buflen = p.fixed(1)
+		*(*int8)(buf) = d
+		ctype = api.SQL_C_STINYINT
+		sqltype = api.SQL_SMALLINT
+		size = 2
+	case uint8:
+		buf, buflen = p.fixed(1)
+		*(*uint8)(buf) = d
+		ctype = api.SQL_C_UTINYINT
+		sqltype = api.SQL_TINYINT
+		size = 1
+	case int16:
+		buf, buflen = p.fixed(2)
+		*(*int16)(buf) = d
+		ctype = api.SQL_C_SSHORT
+		sqltype = api.SQL_SMALLINT
+		size = 2
+	case uint16:
+		buf, buflen = p.fixed(2)
+		*(*uint16)(buf) = d
+		ctype = api.SQL_C_USHORT
+		sqltype = api.SQL_INTEGER
+		size = 4
+	case int32:
+		buf, buflen = p.fixed(4)
+		*(*int32)(buf) = d
+		ctype = api.SQL_C_SLONG
+		sqltype = api.SQL_INTEGER
+		size = 4
+	case uint32:
+		buf, buflen = p.fixed(4)
+		*(*uint32)(buf) = d
+		ctype = api.SQL_C_ULONG
+		sqltype = api.SQL_BIGINT
+		size = 8
+	case uint64:
+		buf, buflen = p.fixed(8)
+		*(*uint64)(buf) = d
+		ctype = api.SQL_C_UBIGINT
+		if d <= math.MaxInt64 {
+			sqltype = api.SQL_BIGINT
+			size = 8
+		} else {
+			// Too big for signed BIGINT.
+			sqltype = api.SQL_DECIMAL
+			size = 20
+		}
+	case float32:
+		buf, buflen = p.fixed(4)
+		*(*float32)(buf) = d
+		ctype = api.SQL_C_FLOAT
+		sqltype = api.SQL_REAL
+		size = 4
+	case [16]byte:
+		buf, buflen = p.fixed(int(unsafe.Sizeof(api.SQLGUID{})))
+		*(*api.SQLGUID)(buf) = guidFromBytes(d)
+		ctype = api.SQL_C_GUID
+		sqltype = api.SQL_GUID
+		size = 16
+	case Decimal:
+		s := d.String()
+		b := p.buffer(len(s) + 1)
+		copy(b, s)
+		b[len(s)] = 0
+		buf = unsafe.Pointer(&b[0])
+		buflen = api.SQLLEN(len(s))
+		precision, scale := d.precisionAndScale()
+		ctype = api.SQL_C_CHAR
+		sqltype = api.SQL_DECIMAL
+		size = api.SQLULEN(precision)
+		decimal = api.SQLSMALLINT(scale)
 	case bool:
-		var b byte
+		if conn.dialect.BoolAsInteger {
+			buf, buflen = p.fixed(4)
+			*(*int32)(buf) = 0
+			if d {
+				*(*int32)(buf) = 1
+			}
+			ctype = api.SQL_C_LONG
+			sqltype = api.SQL_INTEGER
+			size = 4
+			break
+		}
+		buf, buflen = p.fixed(1)
+		*(*byte)(buf) = 0
 		if d {
-			b = 1
+			*(*byte)(buf) = 1
 		}
 		ctype = api.SQL_C_BIT
-		p.Data = &b
-		buf = unsafe.Pointer(&b)
 		sqltype = api.SQL_BIT
 		size = 1
 	case float64:
+		buf, buflen = p.fixed(8)
+		*(*float64)(buf) = d
 		ctype = api.SQL_C_DOUBLE
-		p.Data = &d
-		buf = unsafe.Pointer(&d)
 		sqltype = api.SQL_DOUBLE
 		size = 8
 	case time.Time:
 		ctype = api.SQL_C_TYPE_TIMESTAMP
 		y, m, day := d.Date()
-		b := api.SQL_TIMESTAMP_STRUCT{
+		buf, buflen = p.fixed(int(unsafe.Sizeof(api.SQL_TIMESTAMP_STRUCT{})))
+		*(*api.SQL_TIMESTAMP_STRUCT)(buf) = api.SQL_TIMESTAMP_STRUCT{
 			Year:     api.SQLSMALLINT(y),
 			Month:    api.SQLUSMALLINT(m),
 			Day:      api.SQLUSMALLINT(day),
@@ -126,8 +302,6 @@ func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Con
 			Second:   api.SQLUSMALLINT(d.Second()),
 			Fraction: api.SQLUINTEGER(d.Nanosecond()),
 		}
-		p.Data = &b
-		buf = unsafe.Pointer(&b)
 		sqltype = api.SQL_TYPE_TIMESTAMP
 		if p.isDescribed && p.SQLType == api.SQL_TYPE_TIMESTAMP {
 			decimal = p.Decimal
@@ -139,13 +313,11 @@ func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Con
 		size = 20 + api.SQLULEN(decimal)
 	case []byte:
 		ctype = api.SQL_C_BINARY
-		b := make([]byte, len(d))
+		b := p.buffer(len(d))
 		copy(b, d)
-		p.Data = b
 		buf = unsafe.Pointer(&b[0])
-		buflen = api.SQLLEN(len(b))
-		plen = p.StoreStrLen_or_IndPtr(buflen)
-		size = api.SQLULEN(len(b))
+		buflen = api.SQLLEN(len(d))
+		size = api.SQLULEN(len(d))
 		switch {
 		case p.isDescribed:
 			sqltype = p.SQLType
@@ -156,18 +328,134 @@ func (p *Parameter) BindValue(h api.SQLHSTMT, idx int, v driver.Value, conn *Con
 		default:
 			sqltype = api.SQL_BINARY
 		}
+	case io.Reader:
+		// Data is sent by putData, when SQLExecute asks for it.
+		// Driver identifies parameter by buf, see dataAtExecToken.
+		ctype = api.SQL_C_BINARY
+		p.Data = d
+		buf = unsafe.Pointer(p.dataAtExecToken())
+		buflen = 0
+		ind = api.SQL_LEN_DATA_AT_EXEC_OFFSET // length is unknown
+		if p.isDescribed {
+			sqltype = p.SQLType
+			size = p.Size
+		} else {
+			sqltype = api.SQL_LONGVARBINARY
+			size = math.MaxInt32
+		}
 	default:
 		return fmt.Errorf("unsupported type %T", v)
 	}
+	if ind == 0 {
+		ind = buflen
+	}
+	p.StrLen_or_IndPtr = ind
+	b := paramBinding{ctype: ctype, sqltype: sqltype, decimal: decimal, size: size, buf: buf, buflen: buflen}
+	if b == p.bound {
+		// Driver reads new value from the same buffer.
+		return nil
+	}
 	ret := api.SQLBindParameter(h, api.SQLUSMALLINT(idx+1),
 		api.SQL_PARAM_INPUT, ctype, sqltype, size, decimal,
-		api.SQLPOINTER(buf), buflen, plen)
+		api.SQLPOINTER(buf), buflen, &p.StrLen_or_IndPtr)
 	if IsError(ret) {
+		p.bound = paramBinding{}
 		return NewError("SQLBindParameter", h)
 	}
+	p.bound = b
 	return nil
 }
 
+// buffer returns buffer of p, that holds at least n bytes.
+// The buffer is reused by following executions, and only
+// replaced, when it is too small.
+func (p *Parameter) buffer(n int) []byte {
+	if len(p.buf) >= n && len(p.buf) > 0 {
+		return p.buf
+	}
+	if n < 2*len(p.buf) {
+		n = 2 * len(p.buf)
+	}
+	if n < 16 {
+		n = 16 // tiny allocations might be not 8 byte aligned
+	}
+	p.buf = make([]byte, n)
+	return p.buf
+}
+
+// fixed returns address of buffer of p for fixed size value
+// of n bytes, and n converted to SQLLEN.
+func (p *Parameter) fixed(n int) (unsafe.Pointer, api.SQLLEN) {
+	return unsafe.Pointer(&p.buffer(n)[0]), api.SQLLEN(n)
+}
+
+// dataAtExecToken returns address, that p is bound to, when its
+// value is sent with SQLPutData. SQLParamData returns it to tell,
+// which parameter is needed. It does not point to p itself, because
+// memory passed to C must not contain Go pointers.
+func (p *Parameter) dataAtExecToken() api.SQLPOINTER {
+	if p.token == nil {
+		p.token = make([]byte, 1)
+	}
+	return api.SQLPOINTER(unsafe.Pointer(&p.token[0]))
+}
+
+// putUTF16 stores s, UTF-16 encoded and null-terminated, in
+// buffer of p and returns its length in uint16, without the
+// terminating 0. It is equivalent to api.StringToUTF16.
+func (p *Parameter) putUTF16(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	b := p.buffer(2 * (n + 1))
+	u := (*[1 << 29]uint16)(unsafe.Pointer(&b[0]))[: n+1 : n+1]
+	i := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			r1, r2 := utf16.EncodeRune(r)
+			u[i], u[i+1] = uint16(r1), uint16(r2)
+			i += 2
+			continue
+		}
+		u[i] = uint16(r)
+		i++
+	}
+	u[n] = 0
+	return n
+}
+
+// bindNarrowString prepares string d to be sent as UTF-8 (SQL_C_CHAR).
+func (p *Parameter) bindNarrowString(d string, conn *Conn) (ctype, sqltype api.SQLSMALLINT, size api.SQLULEN, buflen api.SQLLEN, buf unsafe.Pointer) {
+	b := p.buffer(len(d) + 1)
+	copy(b, d)
+	b[len(d)] = 0
+	buf = unsafe.Pointer(&b[0])
+	size = api.SQLULEN(len(d))
+	if size < 1 {
+		// size cannot be less then 1 even for empty fields
+		size = 1
+	}
+	buflen = api.SQLLEN(len(d))
+	switch {
+	case conn.dialect.LongStringParams:
+		sqltype = api.SQL_LONGVARCHAR
+	case size >= 8000:
+		sqltype = api.SQL_LONGVARCHAR
+	case p.isDescribed:
+		sqltype = p.SQLType
+	case size <= 1:
+		sqltype = api.SQL_VARCHAR
+	default:
+		sqltype = api.SQL_CHAR
+	}
+	return api.SQL_C_CHAR, sqltype, size, buflen, buf
+}
+
 func ExtractParameters(h api.SQLHSTMT) ([]Parameter, error) {
 	// count parameters
 	var n, nullable api.SQLSMALLINT
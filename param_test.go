@@ -0,0 +1,128 @@
+package odbc
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// testEnum prints as a number, but is not a decimal.
+type testEnum int
+
+func (e testEnum) String() string { return "1" }
+
+// testDecimal is a decimal type of another package.
+type testDecimal struct {
+	s string
+}
+
+func (d *testDecimal) DecimalString() string { return d.s }
+
+func TestCheckNamedValue(t *testing.T) {
+	c := &Conn{}
+	tests := []struct {
+		v    interface{}
+		skip bool
+		want interface{}
+	}{
+		{v: int8(-1), want: int8(-1)},
+		{v: uint16(1), want: uint16(1)},
+		{v: uint64(1 << 63), want: uint64(1 << 63)},
+		{v: uint(7), want: uint64(7)},
+		{v: float32(1.5), want: float32(1.5)},
+		{v: [16]byte{1}, want: [16]byte{1}},
+		{v: Decimal{text: "-12.50"}, want: Decimal{text: "-12.50"}},
+		{v: &testDecimal{"+.5"}, want: Decimal{text: "0.5"}},
+		{v: (*testDecimal)(nil), want: nil},
+		{v: testEnum(2), skip: true},
+		{v: json.Number("12"), skip: true},
+		{v: net.IPv4(127, 0, 0, 1), skip: true},
+		{v: time.Time{}, skip: true},
+		{v: 1, skip: true},
+		{v: "s", skip: true},
+	}
+	for _, test := range tests {
+		nv := &driver.NamedValue{Value: test.v}
+		err := c.CheckNamedValue(nv)
+		if test.skip {
+			if err != driver.ErrSkip {
+				t.Errorf("CheckNamedValue(%T) should return ErrSkip, got %v", test.v, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CheckNamedValue(%T) failed: %v", test.v, err)
+			continue
+		}
+		if nv.Value != test.want {
+			t.Errorf("CheckNamedValue(%T): want %#v, got %#v", test.v, test.want, nv.Value)
+		}
+	}
+	nv := &driver.NamedValue{Value: &testDecimal{"1e3"}}
+	if err := c.CheckNamedValue(nv); err == nil {
+		t.Errorf("CheckNamedValue(%q) should fail", "1e3")
+	}
+	nv = &driver.NamedValue{Value: big.NewInt(1)}
+	if err := c.CheckNamedValue(nv); err != nil {
+		t.Errorf("CheckNamedValue(*big.Int) failed: %v", err)
+	}
+	// *bytes.Buffer is sent as reader, not as string
+	buf := bytes.NewBufferString("1.5")
+	nv = &driver.NamedValue{Value: buf}
+	if err := c.CheckNamedValue(nv); err != nil || nv.Value != buf {
+		t.Errorf("CheckNamedValue(*bytes.Buffer): got %#v, %v", nv.Value, err)
+	}
+}
+
+func TestGUIDFromBytes(t *testing.T) {
+	b := [16]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10}
+	g := guidFromBytes(b)
+	s := fmt.Sprintf("%08x-%04x-%04x-%x", g.Data1, g.Data2, g.Data3, g.Data4[:])
+	if want := "01234567-89ab-cdef-fedcba9876543210"; s != want {
+		t.Errorf("want %s, got %s", want, s)
+	}
+}
+
+func TestParameterPutUTF16(t *testing.T) {
+	var p Parameter
+	for _, s := range []string{"", "abc", "a\U0001F600b", "\xff", "x\x00y", strings.Repeat("long", 100)} {
+		n := p.putUTF16(s)
+		want := api.StringToUTF16(s)
+		got := (*[1 << 29]uint16)(unsafe.Pointer(&p.buf[0]))[: n+1 : n+1]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("putUTF16(%q): want %v, got %v", s, want, got)
+		}
+	}
+}
+
+func TestParameterBuffer(t *testing.T) {
+	var p Parameter
+	b := p.buffer(1)
+	if len(b) != 16 {
+		t.Fatalf("expected 16 byte buffer, got %d", len(b))
+	}
+	if b2 := p.buffer(16); &b2[0] != &b[0] {
+		t.Error("buffer should be reused, when it is big enough")
+	}
+	if b2 := p.buffer(17); len(b2) != 32 {
+		t.Errorf("expected buffer to double to 32 bytes, got %d", len(b2))
+	}
+}
+
+func TestParameterDataAtExecToken(t *testing.T) {
+	var p Parameter
+	token := p.dataAtExecToken()
+	if p.dataAtExecToken() != token {
+		t.Error("data-at-execution token should not change")
+	}
+}
@@ -5,15 +5,24 @@
 package odbc
 
 import (
+	"context"
 	"database/sql/driver"
 	"io"
+	"math"
 	"reflect"
 
 	"github.com/sigmacomputing/odbc/api"
 )
 
 type Rows struct {
-	os *ODBCStmt
+	os        *ODBCStmt
+	ctx       context.Context
+	stopWatch func()
+	// lobGen counts fetched rows, so LOBReader can tell, that
+	// its row is gone. lobCol is the last column read with
+	// SQLGetData in current row.
+	lobGen int
+	lobCol int
 }
 
 func (r *Rows) Columns() []string {
@@ -24,16 +33,73 @@ func (r *Rows) Columns() []string {
 	return names
 }
 
+// ctxErr returns the error of the context r were created with, if any.
+func (r *Rows) ctxErr() error {
+	if r.ctx == nil {
+		return nil
+	}
+	return r.ctx.Err()
+}
+
 func (r *Rows) Next(dest []driver.Value) error {
+	r.lobGen++
+	if err := r.ctxErr(); err != nil {
+		return err
+	}
+	if r.os.rowStatus != nil {
+		return r.nextInRowset(dest)
+	}
+	if err := r.fetch(); err != nil {
+		return err
+	}
+	for i := range dest {
+		v, err := r.value(i)
+		if err != nil {
+			return err
+		}
+		dest[i] = v
+	}
+	return nil
+}
+
+// fetch fetches next row with SQLFetch.
+func (r *Rows) fetch() error {
 	ret := api.SQLFetch(r.os.h)
 	if ret == api.SQL_NO_DATA {
 		return io.EOF
 	}
 	if IsError(ret) {
+		if err := r.ctxErr(); err != nil {
+			return err
+		}
 		return NewError("SQLFetch", r.os.h)
 	}
+	return nil
+}
+
+// value returns value of column i of current row.
+func (r *Rows) value(i int) (driver.Value, error) {
+	v, err := r.os.Cols[i].Value(r.os.h, i)
+	if err != nil {
+		if cerr := r.ctxErr(); cerr != nil {
+			return nil, cerr
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// nextInRowset is Next for block cursors. Rows are
+// served from the rowset fetched by last SQLFetch.
+func (r *Rows) nextInRowset(dest []driver.Value) error {
+	if err := r.os.fetchRowsetRow(); err != nil {
+		if cerr := r.ctxErr(); cerr != nil && err != io.EOF {
+			return cerr
+		}
+		return err
+	}
 	for i := range dest {
-		v, err := r.os.Cols[i].Value(r.os.h, i)
+		v, err := r.os.Cols[i].(*BindableColumn).rowValue(i, r.os.rowIdx)
 		if err != nil {
 			return err
 		}
@@ -43,7 +109,16 @@ func (r *Rows) Next(dest []driver.Value) error {
 }
 
 func (r *Rows) Close() error {
-	return r.os.closeByRows()
+	r.lobGen++
+	if r.stopWatch != nil {
+		r.stopWatch()
+		r.stopWatch = nil
+	}
+	err := r.os.finishOutParams()
+	if e := r.os.closeByRows(); e != nil {
+		return e
+	}
+	return err
 }
 
 func (r *Rows) HasNextResultSet() bool {
@@ -51,11 +126,21 @@ func (r *Rows) HasNextResultSet() bool {
 }
 
 func (r *Rows) NextResultSet() error {
+	r.lobGen++
+	if err := r.ctxErr(); err != nil {
+		return err
+	}
 	ret := api.SQLMoreResults(r.os.h)
 	if ret == api.SQL_NO_DATA {
+		if err := r.os.copyOutParams(); err != nil {
+			return err
+		}
 		return io.EOF
 	}
 	if IsError(ret) {
+		if err := r.ctxErr(); err != nil {
+			return err
+		}
 		return NewError("SQLMoreResults", r.os.h)
 	}
 
@@ -80,13 +165,72 @@ func (r *Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
 
 // ColumnTypeDatabaseTypeName return the database system type name.
 func (r *Rows) ColumnTypeDatabaseTypeName(index int) string {
+	if c := r.baseColumn(index); c != nil {
+		return sqlTypeString(c.SQLType)
+	}
+	return ""
+}
+
+// ColumnTypeLength returns the length of variable length character
+// and binary columns. Length of columns without limit is math.MaxInt64.
+func (r *Rows) ColumnTypeLength(index int) (length int64, ok bool) {
+	c := r.baseColumn(index)
+	if c == nil {
+		return 0, false
+	}
+	switch c.SQLType {
+	case api.SQL_CHAR, api.SQL_VARCHAR, api.SQL_LONGVARCHAR,
+		api.SQL_WCHAR, api.SQL_WVARCHAR, api.SQL_WLONGVARCHAR, api.SQL_SS_XML,
+		api.SQL_BINARY, api.SQL_VARBINARY, api.SQL_LONGVARBINARY:
+		if c.size == 0 {
+			return math.MaxInt64, true
+		}
+		return int64(c.size), true
+	}
+	return 0, false
+}
+
+// ColumnTypePrecisionScale returns precision and scale of decimal
+// columns. For timestamp and time columns scale is the number of
+// digits in fractional seconds.
+func (r *Rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	c := r.baseColumn(index)
+	if c == nil {
+		return 0, 0, false
+	}
+	switch c.SQLType {
+	case api.SQL_NUMERIC, api.SQL_DECIMAL,
+		api.SQL_TYPE_TIMESTAMP, api.SQL_TIMESTAMP, api.SQL_SS_TIME2:
+		return int64(c.size), int64(c.decimal), true
+	}
+	return 0, 0, false
+}
+
+// ColumnSQLType returns SQL type of column index, as reported by SQLDescribeCol.
+func (r *Rows) ColumnSQLType(index int) api.SQLSMALLINT {
+	if c := r.baseColumn(index); c != nil {
+		return c.SQLType
+	}
+	return api.SQL_UNKNOWN_TYPE
+}
+
+// ColumnCType returns C type, that values of column index are fetched as.
+// It describes data returned by RawValue.
+func (r *Rows) ColumnCType(index int) api.SQLSMALLINT {
+	if c := r.baseColumn(index); c != nil {
+		return c.CType
+	}
+	return 0
+}
+
+func (r *Rows) baseColumn(index int) *BaseColumn {
 	switch x := r.os.Cols[index].(type) {
 	case *BindableColumn:
-		return sqlTypeString(x.SQLType)
+		return x.BaseColumn
 	case *NonBindableColumn:
-		return sqlTypeString(x.SQLType)
+		return x.BaseColumn
 	}
-	return ""
+	return nil
 }
 
 func sqlTypeString(sqlt api.SQLSMALLINT) string {
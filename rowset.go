@@ -0,0 +1,89 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sigmacomputing/odbc/api"
+)
+
+// NextRowset fetches next block of rows and returns number of rows in
+// it. Values of the rows are read with RawValue, without conversion into
+// driver.Value. Rowsets have one row, if block fetching is disabled or
+// some columns can not be bound, see Config.FetchSize. NextRowset is
+// meant for code, that converts rows into other formats, and should
+// not be mixed with Next.
+func (r *Rows) NextRowset() (int, error) {
+	r.lobGen++
+	r.lobCol = -1
+	if err := r.ctxErr(); err != nil {
+		return 0, err
+	}
+	if r.os.rowStatus == nil {
+		if err := r.fetch(); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+	n, err := r.os.fetchRowset()
+	if err != nil {
+		if cerr := r.ctxErr(); cerr != nil && err != io.EOF {
+			return 0, cerr
+		}
+		return 0, err
+	}
+	return n, nil
+}
+
+// RawValue returns value of column col in row of rowset fetched by
+// NextRowset, as stored by the driver in C type ColumnCType(col), or
+// nil, if the value is NULL. Character data is not null-terminated.
+// Returned slice is only valid until next call to NextRowset. Columns,
+// that are not bound, are read with SQLGetData, and have to be read
+// in increasing order.
+func (r *Rows) RawValue(col, row int) ([]byte, error) {
+	if r.os.rowStatus != nil {
+		if row < 0 || row >= int(r.os.rowsFetched[0]) {
+			return nil, fmt.Errorf("odbc: row %d is out of rowset of %d rows", row, r.os.rowsFetched[0])
+		}
+		return r.os.Cols[col].(*BindableColumn).rawRowValue(col, row)
+	}
+	if row != 0 {
+		return nil, fmt.Errorf("odbc: row %d is out of rowset of 1 row", row)
+	}
+	switch c := r.os.Cols[col].(type) {
+	case *BindableColumn:
+		return c.rawValue(r.os.h, col)
+	case *NonBindableColumn:
+		return c.rawValue(r.os.h, col)
+	}
+	return nil, fmt.Errorf("odbc: unsupported column #%d type %T", col, r.os.Cols[col])
+}
+
+// fetchRowset fetches next rowset of block cursor
+// and returns number of rows in it.
+func (s *ODBCStmt) fetchRowset() (int, error) {
+	ret := api.SQLFetch(s.h)
+	if ret == api.SQL_NO_DATA {
+		return 0, io.EOF
+	}
+	if IsError(ret) {
+		return 0, NewError("SQLFetch", s.h)
+	}
+	n := int(s.rowsFetched[0])
+	if n == 0 {
+		return 0, io.EOF
+	}
+	for i := 0; i < n; i++ {
+		if s.rowStatus[i] == api.SQL_ROW_ERROR {
+			return 0, fmt.Errorf("odbc: driver failed to fetch row %d of rowset", i)
+		}
+	}
+	// Next continues with following rowset.
+	s.rowIdx = n - 1
+	return n, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package odbc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validSavepointName reports whether name is a plain identifier
+// that can be safely used in savepoint statements.
+func validSavepointName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Tx returns current transaction of c, or nil, if c is not in
+// a transaction. It is meant for use with sql.Conn.Raw.
+func (c *Conn) Tx() *Tx {
+	return c.tx
+}
+
+func (tx *Tx) execSavepoint(name string, format func(*Dialect) string) error {
+	c := tx.c
+	if c.tx != tx {
+		return errors.New("odbc: transaction has already been committed or rolled back")
+	}
+	if !validSavepointName(name) {
+		return fmt.Errorf("odbc: invalid savepoint name %q", name)
+	}
+	if c.dialect.Savepoint == "" {
+		return fmt.Errorf("odbc: savepoints are not supported by %q", c.dbms.Name)
+	}
+	f := format(c.dialect)
+	if f == "" {
+		return nil
+	}
+	return c.execDirect(fmt.Sprintf(f, name))
+}
+
+// Savepoint creates savepoint name in transaction tx.
+func (tx *Tx) Savepoint(name string) error {
+	return tx.execSavepoint(name, func(d *Dialect) string { return d.Savepoint })
+}
+
+// RollbackTo rolls transaction tx back to savepoint name.
+// Transaction remains active.
+func (tx *Tx) RollbackTo(name string) error {
+	return tx.execSavepoint(name, func(d *Dialect) string { return d.RollbackToSavepoint })
+}
+
+// Release releases savepoint name. It does nothing on
+// DBMSes, like SQL Server, that do not release savepoints.
+func (tx *Tx) Release(name string) error {
+	return tx.execSavepoint(name, func(d *Dialect) string { return d.ReleaseSavepoint })
+}
@@ -0,0 +1,24 @@
+package odbc
+
+import "testing"
+
+func TestValidSavepointName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"sp1", true},
+		{"_sp", true},
+		{"SavePoint_2", true},
+		{"", false},
+		{"1sp", false},
+		{"sp 1", false},
+		{"sp;drop table t", false},
+		{"[sp]", false},
+	}
+	for _, tc := range tests {
+		if got := validSavepointName(tc.name); got != tc.valid {
+			t.Errorf("validSavepointName(%q) = %v, want %v", tc.name, got, tc.valid)
+		}
+	}
+}
@@ -5,11 +5,10 @@
 package odbc
 
 import (
+	"context"
 	"database/sql/driver"
 	"errors"
 	"sync"
-
-	"github.com/sigmacomputing/odbc/api"
 )
 
 type Stmt struct {
@@ -17,92 +16,227 @@ type Stmt struct {
 	query string
 	os    *ODBCStmt
 	mu    sync.Mutex
+	// placeholders are parameter markers of query. They are set
+	// only if all markers are named, and then query is prepared
+	// again with markers replaced by ?, if named arguments are
+	// passed. os is nil, if the driver failed to prepare query
+	// with named markers, until arguments are known.
+	placeholders []placeholder
+	// osQuery is query os was prepared from. osNamed is set
+	// if os parameters are bound by name.
+	osQuery string
+	osNamed bool
+	closed  bool
 }
 
 func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
 	if c.bad {
 		return nil, driver.ErrBadConn
 	}
-	os, err := c.PrepareODBCStmt(query)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var phs []placeholder
+	if p := scanPlaceholders(query); allNamedPlaceholders(p) {
+		phs = p
+	}
+	// Scrollable cursor, if configured, is set when the
+	// statement is executed with Query, see prepareFor.
+	os, err := c.prepareODBCStmt(query, CursorForwardOnly)
 	if err != nil {
+		if phs == nil {
+			return nil, err
+		}
+		// The driver may not know named markers. Prepare
+		// again, when the arguments are known.
+		os = nil
+	}
+	if err := ctx.Err(); err != nil {
+		if os != nil {
+			os.closeByStmt()
+		}
 		return nil, err
 	}
-	return &Stmt{c: c, os: os, query: query}, nil
+	return &Stmt{c: c, os: os, query: query, osQuery: query, placeholders: phs}, nil
 }
 
+// NumInput returns -1 for queries with named parameter markers,
+// that the driver does not count as parameters, because the number
+// of arguments depends on whether they are named.
 func (s *Stmt) NumInput() int {
-	if s.os == nil {
+	if s.os == nil || s.placeholders != nil && len(s.os.Parameters) != len(s.placeholders) {
 		return -1
 	}
 	return len(s.os.Parameters)
 }
 
 func (s *Stmt) Close() error {
-	if s.os == nil {
+	if s.closed {
 		return errors.New("Stmt is already closed")
 	}
+	s.closed = true
+	if s.os == nil {
+		return nil
+	}
 	ret := s.os.closeByStmt()
 	s.os = nil
 	return ret
 }
 
-func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
-	if s.os == nil {
-		return nil, errors.New("Stmt is closed")
+// valueToNamedValue converts positional args into driver.NamedValue.
+func valueToNamedValue(args []driver.Value) []driver.NamedValue {
+	nvs := make([]driver.NamedValue, len(args))
+	for i, a := range args {
+		nvs[i] = driver.NamedValue{Ordinal: i + 1, Value: a}
 	}
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.os.usedByRows {
-		s.os.closeByStmt()
-		s.os = nil
-		os, err := s.c.PrepareODBCStmt(s.query)
+	return nvs
+}
+
+// prepareFor makes sure s.os can be executed with args, has cursor
+// of type ct and is not shared with any Rows. It returns argument
+// values in the order of query parameters. Named args replace
+// matching @name or :name markers in query. If query has no such
+// markers, named args are passed to the driver by name
+// (SQL_DESC_NAME), as needed to call stored procedures with
+// named parameters.
+func (s *Stmt) prepareFor(args []driver.NamedValue, ct CursorType) ([]driver.Value, error) {
+	var names []string
+	for _, a := range args {
+		if a.Name != "" {
+			names = append(names, a.Name)
+		}
+	}
+	if len(names) > 0 && len(names) != len(args) {
+		return nil, errors.New("odbc: cannot mix named and positional arguments")
+	}
+	q := s.query
+	vs := make([]driver.Value, len(args))
+	for i, a := range args {
+		vs[i] = a.Value
+	}
+	if len(names) > 0 && s.placeholders != nil {
+		var err error
+		q, vs, err = bindNamed(s.query, s.placeholders, args)
 		if err != nil {
 			return nil, err
 		}
-		s.os = os
+		names = nil
 	}
-	err := s.os.Exec(args, s.c)
+	named := len(names) > 0
+	if s.os != nil && s.osQuery == q && s.osNamed == named && s.os.cursorType == ct && !s.os.usedByRows {
+		s.os.paramNames = names
+		return vs, nil
+	}
+	if s.os != nil {
+		s.os.closeByStmt()
+		s.os = nil
+	}
+	os, err := s.c.prepareODBCStmt(q, ct)
 	if err != nil {
 		return nil, err
 	}
-	var sumRowCount int64
-	for {
-		var c api.SQLLEN
-		ret := api.SQLRowCount(s.os.h, &c)
-		if IsError(ret) {
-			return nil, NewError("SQLRowCount", s.os.h)
-		}
-		sumRowCount += int64(c)
-		if ret = api.SQLMoreResults(s.os.h); ret == api.SQL_NO_DATA {
-			break
+	if named {
+		// Markers are described in query order, which
+		// is not necessarily the order of named args.
+		for i := range os.Parameters {
+			os.Parameters[i].isDescribed = false
 		}
 	}
-	return &Result{rowCount: sumRowCount}, nil
+	s.os = os
+	s.osQuery = q
+	s.osNamed = named
+	s.os.paramNames = names
+	return vs, nil
 }
 
-func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
-	if s.os == nil {
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valueToNamedValue(args))
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if s.closed {
 		return nil, errors.New("Stmt is closed")
 	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.os.usedByRows {
-		s.os.closeByStmt()
-		s.os = nil
-		os, err := s.c.PrepareODBCStmt(s.query)
-		if err != nil {
-			return nil, err
+	vs, err := s.prepareFor(args, CursorForwardOnly)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.os.setQueryTimeout(ctx, s.c.cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+	stop := s.os.watchCancel(ctx)
+	r, err := s.execAndCount(vs)
+	stop()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-		s.os = os
+		return nil, wrapTimeout(err)
 	}
+	return r, nil
+}
+
+func (s *Stmt) execAndCount(args []driver.Value) (driver.Result, error) {
 	err := s.os.Exec(args, s.c)
 	if err != nil {
 		return nil, err
 	}
-	err = s.os.BindColumns()
+	sumRowCount, err := s.os.rowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.os.copyOutParams(); err != nil {
+		return nil, err
+	}
+	return &Result{rowCount: sumRowCount}, nil
+}
+
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valueToNamedValue(args))
+}
+
+// QueryContext implements driver.StmtQueryContext.
+// The returned Rows cancel the statement if ctx is done
+// before they are closed.
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if s.closed {
+		return nil, errors.New("Stmt is closed")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vs, err := s.prepareFor(args, s.c.cfg.CursorType)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.os.setQueryTimeout(ctx, s.c.cfg.QueryTimeout); err != nil {
+		return nil, err
+	}
+	stop := s.os.watchCancel(ctx)
+	err = s.os.Exec(vs, s.c)
+	if err == nil {
+		err = s.os.BindColumns()
+	}
+	if err != nil {
+		stop()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, wrapTimeout(err)
+	}
 	s.os.usedByRows = true // now both Stmt and Rows refer to it
-	return &Rows{os: s.os}, nil
+	return &Rows{os: s.os, ctx: ctx, stopWatch: stop}, nil
 }
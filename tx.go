@@ -5,37 +5,133 @@
 package odbc
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 
 	"github.com/sigmacomputing/odbc/api"
 )
 
 type Tx struct {
 	c *Conn
+	// saved holds connection attributes changed by BeginTx
+	// together with their values before the change.
+	saved []ConnAttr
 }
 
 var testBeginErr error // used during tests
 
+// isolationLevels maps database/sql isolation levels
+// to SQL_ATTR_TXN_ISOLATION values.
+var isolationLevels = map[sql.IsolationLevel]uintptr{
+	sql.LevelReadUncommitted: api.SQL_TXN_READ_UNCOMMITTED,
+	sql.LevelReadCommitted:   api.SQL_TXN_READ_COMMITTED,
+	sql.LevelRepeatableRead:  api.SQL_TXN_REPEATABLE_READ,
+	sql.LevelSerializable:    api.SQL_TXN_SERIALIZABLE,
+	sql.LevelSnapshot:        api.SQL_TXN_SS_SNAPSHOT,
+}
+
 func (c *Conn) setAutoCommitAttr(a uintptr) error {
 	if testBeginErr != nil {
 		return testBeginErr
 	}
-	ret := api.SQLSetConnectUIntPtrAttr(c.h, api.SQL_ATTR_AUTOCOMMIT, a, api.SQL_IS_UINTEGER)
+	return c.setConnectAttr(api.SQL_ATTR_AUTOCOMMIT, a)
+}
+
+func (c *Conn) setConnectAttr(attr api.SQLINTEGER, v uintptr) error {
+	ret := api.SQLSetConnectUIntPtrAttr(c.h, attr, v, api.SQL_IS_UINTEGER)
 	if IsError(ret) {
 		return c.newError("SQLSetConnectUIntPtrAttr", c.h)
 	}
 	return nil
 }
 
+// isolationLevel returns SQL_ATTR_TXN_ISOLATION value for level l.
+// It returns an error, if the driver does not support l.
+func (c *Conn) isolationLevel(l driver.IsolationLevel) (uintptr, error) {
+	level := sql.IsolationLevel(l)
+	v, ok := isolationLevels[level]
+	if level == sql.LevelSnapshot && !c.dialect.SnapshotIsolation {
+		ok = false
+	}
+	if !ok {
+		return 0, fmt.Errorf("odbc: unsupported isolation level: %v", level)
+	}
+	supported, err := c.InfoUint32(api.SQL_TXN_ISOLATION_OPTION)
+	if err != nil {
+		return 0, err
+	}
+	if uintptr(supported)&v == 0 {
+		return 0, fmt.Errorf("odbc: isolation level %v is not supported by the driver", level)
+	}
+	return v, nil
+}
+
+// setAttr sets connection attribute attr to v, and remembers
+// its current value, so it can be restored when tx ends.
+func (tx *Tx) setAttr(attr api.SQLINTEGER, v uintptr) error {
+	old, err := tx.c.getConnectAttr(attr)
+	if err != nil {
+		return err
+	}
+	if old == v {
+		return nil
+	}
+	if err := tx.c.setConnectAttr(attr, v); err != nil {
+		return err
+	}
+	tx.saved = append(tx.saved, ConnAttr{Attr: attr, Value: old})
+	return nil
+}
+
+// restoreAttrs restores connection attributes changed by setAttr.
+func (tx *Tx) restoreAttrs() error {
+	for i := len(tx.saved) - 1; i >= 0; i-- {
+		a := tx.saved[i]
+		if err := tx.c.setConnectAttr(a.Attr, a.Value); err != nil {
+			return err
+		}
+	}
+	tx.saved = nil
+	return nil
+}
+
 func (c *Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx implements driver.ConnBeginTx. Isolation level is set
+// with SQL_ATTR_TXN_ISOLATION and read-only transactions use
+// SQL_ATTR_ACCESS_MODE. Both are restored when transaction ends.
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
 	if c.bad {
 		return nil, driver.ErrBadConn
 	}
 	if c.tx != nil {
 		return nil, errors.New("already in a transaction")
 	}
-	c.tx = &Tx{c: c}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tx := &Tx{c: c}
+	if sql.IsolationLevel(opts.Isolation) != sql.LevelDefault {
+		level, err := c.isolationLevel(opts.Isolation)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.setAttr(api.SQL_ATTR_TXN_ISOLATION, level); err != nil {
+			return nil, err
+		}
+	}
+	if opts.ReadOnly {
+		if err := tx.setAttr(api.SQL_ATTR_ACCESS_MODE, api.SQL_MODE_READ_ONLY); err != nil {
+			tx.restoreAttrs()
+			return nil, err
+		}
+	}
+	c.tx = tx
 	err := c.setAutoCommitAttr(api.SQL_AUTOCOMMIT_OFF)
 	if err != nil {
 		c.bad = true
@@ -59,12 +155,18 @@ func (c *Conn) endTx(commit bool) error {
 		c.bad = true
 		return c.newError("SQLEndTran", c.h)
 	}
+	tx := c.tx
 	c.tx = nil
 	err := c.setAutoCommitAttr(api.SQL_AUTOCOMMIT_ON)
 	if err != nil {
 		c.bad = true
 		return err
 	}
+	err = tx.restoreAttrs()
+	if err != nil {
+		c.bad = true
+		return err
+	}
 	return nil
 }
 
@@ -37,6 +37,16 @@ func TestExtractTimezoneFromDsn(t *testing.T) {
 			loc:         loc(t, "UTC"),
 			shouldError: false,
 		},
+		{
+			dsn:         "Driver=something;ApplySSPWithQueries=0;SSP_timezone=Europe/Paris",
+			loc:         loc(t, "Europe/Paris"),
+			shouldError: false,
+		},
+		{
+			dsn:         "Driver=something;ssp_TIMEZONE={Asia/Tokyo};",
+			loc:         loc(t, "Asia/Tokyo"),
+			shouldError: false,
+		},
 		{
 			dsn:         "Driver=something;ApplySSPWithQueries=0;",
 			loc:         nil,